@@ -1,49 +1,76 @@
 // Package env creates and updates the env.json file.
+//
+// Values can be encrypted in place with EncryptValue so the file can be
+// committed. Use EncodedKey(32) to create the master key and store it in the
+// JAYKEY environment variable or in a file referenced by JAYKEYFILE.
 package env
 
 import (
-	"bufio"
 	"crypto/rand"
 	"encoding/base64"
 	"fmt"
 	"io/ioutil"
-	"os"
 	"strings"
 )
 
-// UpdateFileKeys updates the session keys in the env.json.
+// Key is a secret in the env.json file.
+type Key struct {
+	// Path is the dot separated path to the key like Session.AuthKey
+	Path string
+	// Length is the number of random bytes before base64 encoding
+	Length int
+}
+
+// DefaultKeys are the secrets that are updated by UpdateFileKeys.
+var DefaultKeys = []Key{
+	{"Session.AuthKey", 64},
+	{"Session.EncryptKey", 32},
+	{"Session.CSRFKey", 32},
+}
+
+// UpdateFileKeys updates the session keys in the env.json. Keys from
+// DefaultKeys that are not in the file are skipped.
 func UpdateFileKeys(src string) error {
-	file, err := os.Open(src)
+	return updateKeys(src, DefaultKeys, true)
+}
+
+// UpdateKeys sets each key in the file to a new random value with the
+// length of the key. The rest of the file, including the formatting and the
+// order of the keys, is left as is. An error is returned if a key is not in
+// the file.
+func UpdateKeys(src string, keys ...Key) error {
+	return updateKeys(src, keys, false)
+}
+
+// updateKeys sets each key in the file to a new random value.
+func updateKeys(src string, keys []Key, skipMissing bool) error {
+	data, err := ioutil.ReadFile(src)
 	if err != nil {
 		return err
 	}
 
-	var newFile string
-
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
+	data, err = setKeys(data, keys, skipMissing)
+	if err != nil {
+		return err
+	}
 
-		if len(newFile) > 0 {
-			newFile += "\n"
-		}
+	return ioutil.WriteFile(src, data, 0644)
+}
 
-		switch {
-		case strings.Contains(scanner.Text(), `"AuthKey"`):
-			newFile += fmt.Sprintf(`    "AuthKey":"%v",`, EncodedKey(64))
-		case strings.Contains(scanner.Text(), `"CSRFKey"`):
-			newFile += fmt.Sprintf(`    "CSRFKey":"%v",`, EncodedKey(32))
-		default:
-			newFile += scanner.Text()
+// setKeys returns the JSON document with each key set to a new random value.
+func setKeys(data []byte, keys []Key, skipMissing bool) ([]byte, error) {
+	for _, k := range keys {
+		start, end, err := valueOffset(data, strings.Split(k.Path, "."))
+		if err == errKeyMissing && skipMissing {
+			continue
+		} else if err != nil {
+			return nil, fmt.Errorf("%v: %v", k.Path, err)
 		}
-	}
 
-	if err := scanner.Err(); err != nil {
-		file.Close()
-		return err
+		data = replaceValue(data, start, end, EncodedKey(k.Length))
 	}
 
-	file.Close()
-	return ioutil.WriteFile(src, []byte(newFile), 0644)
+	return data, nil
 }
 
 // EncodedKey returns a base64 encoded random key.
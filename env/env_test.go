@@ -2,7 +2,10 @@ package env_test
 
 import (
 	"bytes"
+	"encoding/base64"
+	"encoding/json"
 	"io/ioutil"
+	"strings"
 	"testing"
 
 	"github.com/blue-jay/core/env"
@@ -64,3 +67,90 @@ func TestMissingFile(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+// TestUpdateFileFormat ensures only the key values change.
+func TestUpdateFileFormat(t *testing.T) {
+	file, cleanup := tempFile(t, "testdata/envtest.json")
+	defer cleanup()
+
+	b1, err := ioutil.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = env.UpdateFileKeys(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b2, err := ioutil.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Replace each key with a fixed value and compare the files
+	mask := func(b []byte) string {
+		list := strings.Split(string(b), "\n")
+		for i, line := range list {
+			for _, k := range []string{`"AuthKey":"`, `"EncryptKey":"`, `"CSRFKey":"`} {
+				if n := strings.Index(line, k); n >= 0 {
+					list[i] = line[:n+len(k)] + "KEY"
+				}
+			}
+		}
+		return strings.Join(list, "\n")
+	}
+
+	if mask(b1) != mask(b2) {
+		t.Fatalf("\n got: %s\nwant: %s", mask(b2), mask(b1))
+	}
+
+	for _, k := range []string{"AuthKey", "EncryptKey", "CSRFKey"} {
+		if keyValue(b1, k) == keyValue(b2, k) {
+			t.Errorf("%v should have changed.", k)
+		}
+	}
+}
+
+// TestUpdateKeys ensures only the selected keys are updated with the length.
+func TestUpdateKeys(t *testing.T) {
+	file, cleanup := tempFile(t, "testdata/envtest.json")
+	defer cleanup()
+
+	b1, _ := ioutil.ReadFile(file)
+
+	err := env.UpdateKeys(file, env.Key{"Session.CSRFKey", 16})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b2, _ := ioutil.ReadFile(file)
+
+	if keyValue(b1, "AuthKey") != keyValue(b2, "AuthKey") {
+		t.Error("AuthKey should not have changed.")
+	}
+
+	key, err := base64.StdEncoding.DecodeString(keyValue(b2, "CSRFKey"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(key) != 16 {
+		t.Errorf("\n got: %v\nwant: %v", len(key), 16)
+	}
+
+	err = env.UpdateKeys(file, env.Key{"Session.Missing", 32})
+	if err == nil {
+		t.Fatal("UpdateKeys should have failed because the key is missing.")
+	}
+}
+
+// keyValue returns the value of the session key.
+func keyValue(b []byte, key string) string {
+	v := struct {
+		Session map[string]interface{}
+	}{}
+	json.Unmarshal(b, &v)
+	s, _ := v.Session[key].(string)
+	return s
+}
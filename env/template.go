@@ -0,0 +1,172 @@
+package env
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+)
+
+const (
+	// Missing is when a key in the template is not in the file
+	Missing = "missing"
+	// Extra is when a key in the file is not in the template
+	Extra = "extra"
+	// Mismatch is when a key has a different type in the file
+	Mismatch = "mismatch"
+)
+
+// Difference is a key that does not match between the template and the file.
+type Difference struct {
+	// Kind is Missing, Extra, or Mismatch
+	Kind string
+	// Path is the dot separated path to the key
+	Path string
+	// Expected is the JSON type in the template
+	Expected string
+	// Actual is the JSON type in the file
+	Actual string
+}
+
+// String returns a line that describes the difference.
+func (d Difference) String() string {
+	switch d.Kind {
+	case Missing:
+		return fmt.Sprintf("%v: %v (%v)", d.Kind, d.Path, d.Expected)
+	case Extra:
+		return fmt.Sprintf("%v: %v (%v)", d.Kind, d.Path, d.Actual)
+	}
+
+	return fmt.Sprintf("%v: %v (%v, not %v)", d.Kind, d.Path, d.Expected, d.Actual)
+}
+
+// CreateFile copies the template, like env.json.example, to dst and sets the
+// keys to new random values. If no keys are passed, the keys from DefaultKeys
+// that are in the template are used. An error is returned if dst already
+// exists. Nothing is written if a key is not in the template.
+func CreateFile(template string, dst string, keys ...Key) error {
+	data, err := ioutil.ReadFile(template)
+	if err != nil {
+		return err
+	}
+
+	if _, err := os.Stat(dst); err == nil {
+		return fmt.Errorf("File already exists: %v", dst)
+	}
+
+	// Set the keys before writing so a failure does not leave a file behind
+	if len(keys) == 0 {
+		data, err = setKeys(data, DefaultKeys, true)
+	} else {
+		data, err = setKeys(data, keys, false)
+	}
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(dst, data, 0644)
+}
+
+// Check compares the file against the template and returns every key that is
+// missing, extra, or has a different type, sorted by path. An empty list means
+// the file matches the template.
+func Check(template string, src string) ([]Difference, error) {
+	expected, err := readJSON(template)
+	if err != nil {
+		return nil, err
+	}
+
+	actual, err := readJSON(src)
+	if err != nil {
+		return nil, err
+	}
+
+	var list []Difference
+	compare("", expected, actual, &list)
+
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Path < list[j].Path
+	})
+
+	return list, nil
+}
+
+// readJSON returns the decoded JSON file.
+func readJSON(file string) (interface{}, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, fmt.Errorf("%v: %v", file, err)
+	}
+
+	return v, nil
+}
+
+// compare adds the differences between the expected and actual values.
+func compare(path string, expected, actual interface{}, list *[]Difference) {
+	e, a := jsonType(expected), jsonType(actual)
+
+	// A null value in either file can be any type
+	if e == "null" || a == "null" {
+		return
+	}
+
+	if e != a {
+		*list = append(*list, Difference{Mismatch, path, e, a})
+		return
+	}
+
+	// Only objects are compared key by key
+	em, ok := expected.(map[string]interface{})
+	if !ok {
+		return
+	}
+	am := actual.(map[string]interface{})
+
+	for k, ev := range em {
+		child := joinKey(path, k)
+		if av, ok := am[k]; ok {
+			compare(child, ev, av, list)
+		} else {
+			*list = append(*list, Difference{Missing, child, jsonType(ev), ""})
+		}
+	}
+
+	for k, av := range am {
+		if _, ok := em[k]; !ok {
+			*list = append(*list, Difference{Extra, joinKey(path, k), "", jsonType(av)})
+		}
+	}
+}
+
+// joinKey appends the key to the path.
+func joinKey(path, key string) string {
+	if len(path) == 0 {
+		return key
+	}
+
+	return path + "." + key
+}
+
+// jsonType returns the name of the JSON type of the decoded value.
+func jsonType(v interface{}) string {
+	switch v.(type) {
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	}
+
+	return "null"
+}
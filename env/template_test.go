@@ -0,0 +1,143 @@
+package env_test
+
+import (
+	"encoding/base64"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/blue-jay/core/env"
+)
+
+// TestCreateFile ensures the file is created with new keys.
+func TestCreateFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "env")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	dst := filepath.Join(dir, "env.json")
+
+	err = env.CreateFile("testdata/env.json.example", dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := ioutil.ReadFile(dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key, err := base64.StdEncoding.DecodeString(keyValue(b, "AuthKey"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(key) != 64 {
+		t.Errorf("\n got: %v\nwant: %v", len(key), 64)
+	}
+
+	// The file should match the template
+	list, err := env.Check("testdata/env.json.example", dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(list) > 0 {
+		t.Errorf("File should match the template: %v", list)
+	}
+
+	err = env.CreateFile("testdata/env.json.example", dst)
+	if err == nil {
+		t.Fatal("CreateFile should have failed because the file exists.")
+	}
+}
+
+// TestCreateFileFail ensures no file is left when a key is missing.
+func TestCreateFileFail(t *testing.T) {
+	dir, err := ioutil.TempDir("", "env")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	dst := filepath.Join(dir, "env.json")
+
+	err = env.CreateFile("testdata/env.json.example", dst, env.Key{Path: "Session.Missing", Length: 32})
+	if err == nil {
+		t.Fatal("CreateFile should have failed because the key is missing.")
+	}
+
+	if _, err := os.Stat(dst); !os.IsNotExist(err) {
+		t.Fatalf("File should not exist: %v", err)
+	}
+
+	// A retry with the right key works
+	err = env.CreateFile("testdata/env.json.example", dst, env.Key{Path: "Session.AuthKey", Length: 64})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestCheck ensures missing, extra, and mismatched keys are reported.
+func TestCheck(t *testing.T) {
+	list, err := env.Check("testdata/env.json.example", "testdata/envtest.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		"missing: Email (object)",
+	}
+
+	received := make([]string, len(list))
+	for i, d := range list {
+		received[i] = d.String()
+	}
+
+	if len(received) != len(expected) {
+		t.Fatalf("\n got: %v\nwant: %v", received, expected)
+	}
+
+	for i := range expected {
+		if received[i] != expected[i] {
+			t.Errorf("\n got: %v\nwant: %v", received[i], expected[i])
+		}
+	}
+}
+
+// TestCheckDrift ensures every kind of difference is reported.
+func TestCheckDrift(t *testing.T) {
+	list, err := env.Check("testdata/env.json.example", "testdata/envdrift.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		"missing: Email.Port (number)",
+		"mismatch: Session.Options.MaxAge (number, not string)",
+		"extra: Session.Store (string)",
+	}
+
+	received := make([]string, len(list))
+	for i, d := range list {
+		received[i] = d.String()
+	}
+
+	if len(received) != len(expected) {
+		t.Fatalf("\n got: %v\nwant: %v", received, expected)
+	}
+
+	for i := range expected {
+		if received[i] != expected[i] {
+			t.Errorf("\n got: %v\nwant: %v", received[i], expected[i])
+		}
+	}
+
+	_, err = env.Check("testdata/env.json.example", "testdata/envtest-missing.json")
+	if err == nil {
+		t.Fatal("File should have failed because it is missing.")
+	}
+}
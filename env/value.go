@@ -0,0 +1,126 @@
+package env
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/blue-jay/core/jsonconfig"
+)
+
+// errKeyMissing is when the key is not in the file.
+var errKeyMissing = errors.New("key is missing")
+
+// EncryptValue seals the string value at the key path in the file with the
+// master key. The path is separated by dots like MySQL.Password. The rest of
+// the file is left as is.
+func EncryptValue(src string, path string, key []byte) error {
+	return updateValue(src, path, func(s string) (string, error) {
+		if strings.HasPrefix(s, jsonconfig.SealedPrefix) {
+			return "", fmt.Errorf("%v is already encrypted.", path)
+		}
+		return jsonconfig.Seal(key, s)
+	})
+}
+
+// DecryptValue opens the sealed string value at the key path in the file with
+// the master key. The path is separated by dots like MySQL.Password. The rest
+// of the file is left as is.
+func DecryptValue(src string, path string, key []byte) error {
+	return updateValue(src, path, func(s string) (string, error) {
+		if !strings.HasPrefix(s, jsonconfig.SealedPrefix) {
+			return "", fmt.Errorf("%v is not encrypted.", path)
+		}
+		return jsonconfig.Open(key, s)
+	})
+}
+
+// updateValue replaces the string value at the key path in the file with the
+// result of fn.
+func updateValue(src string, path string, fn func(string) (string, error)) error {
+	data, err := ioutil.ReadFile(src)
+	if err != nil {
+		return err
+	}
+
+	start, end, err := valueOffset(data, strings.Split(path, "."))
+	if err != nil {
+		return fmt.Errorf("%v: %v", path, err)
+	}
+
+	var s string
+	if err := json.Unmarshal(data[start:end], &s); err != nil {
+		return fmt.Errorf("%v is not a string.", path)
+	}
+
+	s, err = fn(s)
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(src, replaceValue(data, start, end, s), 0644)
+}
+
+// replaceValue returns a copy of data with the value between the offsets
+// replaced by the JSON encoded string.
+func replaceValue(data []byte, start, end int, s string) []byte {
+	// Encoding a string never fails
+	b, _ := json.Marshal(s)
+
+	out := make([]byte, 0, len(data)+len(b))
+	out = append(out, data[:start]...)
+	out = append(out, b...)
+	return append(out, data[end:]...)
+}
+
+// valueOffset returns the start and end offset of the value at the path in the
+// JSON document so it can be replaced without changing the formatting.
+func valueOffset(data []byte, path []string) (int, int, error) {
+	d := json.NewDecoder(bytes.NewReader(data))
+
+	for depth, key := range path {
+		// The value must be an object to contain the key
+		if t, err := d.Token(); err != nil {
+			return 0, 0, err
+		} else if t != json.Delim('{') {
+			return 0, 0, fmt.Errorf("%v is not an object.", strings.Join(path[:depth], "."))
+		}
+
+		found := false
+
+		for d.More() {
+			t, err := d.Token()
+			if err != nil {
+				return 0, 0, err
+			}
+
+			if t == key {
+				found = true
+				break
+			}
+
+			// Skip the value
+			var raw json.RawMessage
+			if err := d.Decode(&raw); err != nil {
+				return 0, 0, err
+			}
+		}
+
+		if !found {
+			return 0, 0, errKeyMissing
+		}
+	}
+
+	// Read the value to find where it ends
+	var raw json.RawMessage
+	if err := d.Decode(&raw); err != nil {
+		return 0, 0, err
+	}
+
+	end := int(d.InputOffset())
+
+	return end - len(raw), end, nil
+}
@@ -0,0 +1,99 @@
+package env_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/blue-jay/core/env"
+)
+
+// masterKey is a test key.
+var masterKey = []byte("0123456789abcdef0123456789abcdef")
+
+// tempFile copies the file to a temporary folder.
+func tempFile(t *testing.T, src string) (string, func()) {
+	dir, err := ioutil.TempDir("", "env")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := ioutil.ReadFile(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	file := filepath.Join(dir, filepath.Base(src))
+	if err := ioutil.WriteFile(file, b, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	return file, func() { os.RemoveAll(dir) }
+}
+
+// TestEncryptValue ensures a value is encrypted and decrypted in place.
+func TestEncryptValue(t *testing.T) {
+	file, cleanup := tempFile(t, "testdata/envtest.json")
+	defer cleanup()
+
+	b1, _ := ioutil.ReadFile(file)
+
+	err := env.EncryptValue(file, "Session.Name", masterKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b2, _ := ioutil.ReadFile(file)
+
+	if strings.Contains(string(b2), `"sess"`) || !strings.Contains(string(b2), `"Name":"enc:`) {
+		t.Fatalf("Value should be encrypted:\n%s", b2)
+	}
+
+	// Encrypting twice should fail
+	err = env.EncryptValue(file, "Session.Name", masterKey)
+	if err == nil {
+		t.Fatal("Value should already be encrypted.")
+	}
+
+	err = env.DecryptValue(file, "Session.Name", masterKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b3, _ := ioutil.ReadFile(file)
+
+	if string(b1) != string(b3) {
+		t.Fatalf("\n got: %s\nwant: %s", b3, b1)
+	}
+}
+
+// TestEncryptValueFail ensures missing and non-string values fail.
+func TestEncryptValueFail(t *testing.T) {
+	file, cleanup := tempFile(t, "testdata/envtest.json")
+	defer cleanup()
+
+	tests := []string{
+		"Session.Missing",
+		"Session.Options",
+		"Session.Options.MaxAge",
+		"Session.Name.Value",
+	}
+
+	for _, path := range tests {
+		if err := env.EncryptValue(file, path, masterKey); err == nil {
+			t.Errorf("EncryptValue should have failed: %v", path)
+		}
+	}
+
+	err := env.DecryptValue(file, "Session.Name", masterKey)
+	if err == nil {
+		t.Fatal("Value should not be encrypted.")
+	}
+
+	err = env.EncryptValue("testdata/envtest-missing.json", "Session.Name", masterKey)
+	if err == nil {
+		t.Fatal("File should have failed because it is missing.")
+	}
+}
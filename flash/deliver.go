@@ -0,0 +1,188 @@
+package flash
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/blue-jay/core/session"
+	"github.com/gorilla/sessions"
+)
+
+var (
+	// Header is the response header with the flashes as JSON for Ajax
+	// requests from Deliver
+	Header = "X-Flash"
+	// HTMXEvent is the HX-Trigger event with the flashes for HTMX requests
+	// from Deliver
+	HTMXEvent = "flash"
+)
+
+// Envelope is the response for a JSON API with the flashes.
+type Envelope struct {
+	Data    interface{} `json:"Data"`
+	Flashes []Message   `json:"Flashes"`
+}
+
+// SendJSON writes the data and the flashes in an Envelope and removes the
+// flashes from the session.
+func SendJSON(w http.ResponseWriter, r *http.Request, sess Session, status int, data interface{}) error {
+	env := Envelope{
+		Data:    data,
+		Flashes: Messages(w, r, sess),
+	}
+	if env.Flashes == nil {
+		env.Flashes = []Message{}
+	}
+
+	js, err := json.Marshal(env)
+	if err != nil {
+		return err
+	}
+
+	save(w, r, sess)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, err = w.Write(js)
+	return err
+}
+
+// save saves the session unless it is from the session.Middleware which saves
+// it once for the request.
+func save(w http.ResponseWriter, r *http.Request, sess Session) {
+	if s, ok := sess.(*sessions.Session); ok && s == session.Current(r) {
+		return
+	}
+
+	sess.Save(r, w)
+}
+
+// Deliver sets the flashes in a response header for HTMX and Ajax requests so
+// partial page updates can show them. HTMX requests get an HX-Trigger event
+// and other Ajax requests get the Header. Redirects and full page requests
+// keep the flashes for the view. It requires the session.Middleware and must
+// be after it in the chain.
+func Deliver(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		htmx := r.Header.Get("HX-Request") == "true"
+		ajax := r.Header.Get("X-Requested-With") == "XMLHttpRequest" ||
+			strings.Contains(r.Header.Get("Accept"), "application/json")
+
+		if !htmx && !ajax {
+			h.ServeHTTP(w, r)
+			return
+		}
+
+		h.ServeHTTP(&deliverWriter{ResponseWriter: w, r: r, htmx: htmx}, r)
+	})
+}
+
+// deliverWriter sets the flash header before the headers are written.
+type deliverWriter struct {
+	http.ResponseWriter
+	r     *http.Request
+	htmx  bool
+	wrote bool
+}
+
+func (w *deliverWriter) WriteHeader(code int) {
+	w.deliver(code)
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *deliverWriter) Write(b []byte) (int, error) {
+	w.deliver(http.StatusOK)
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush sends the buffered data if the ResponseWriter supports it.
+func (w *deliverWriter) Flush() {
+	w.deliver(http.StatusOK)
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack takes over the connection if the ResponseWriter supports it. The
+// flashes are kept in the session since the header is never sent.
+func (w *deliverWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, http.ErrNotSupported
+	}
+
+	w.wrote = true
+	return h.Hijack()
+}
+
+// Unwrap returns the original ResponseWriter.
+func (w *deliverWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// deliver removes the flashes from the session and sets the header.
+func (w *deliverWriter) deliver(code int) {
+	if w.wrote {
+		return
+	}
+	w.wrote = true
+
+	// Keep the flashes for the page after a redirect
+	if code >= 300 && code < 400 {
+		return
+	}
+
+	sess := session.Current(w.r)
+	if sess == nil {
+		return
+	}
+
+	flashes := Messages(w, w.r, sess)
+	if len(flashes) == 0 {
+		return
+	}
+
+	if !w.htmx {
+		w.Header().Set(Header, headerJSON(flashes))
+		return
+	}
+
+	// Add the event to the events from the handler
+	events := make(map[string]interface{})
+	if v := w.Header().Get("HX-Trigger"); len(v) > 0 {
+		if err := json.Unmarshal([]byte(v), &events); err != nil {
+			for _, name := range strings.Split(v, ",") {
+				events[strings.TrimSpace(name)] = nil
+			}
+		}
+	}
+	events[HTMXEvent] = flashes
+
+	w.Header().Set("HX-Trigger", headerJSON(events))
+}
+
+// headerJSON returns the value as JSON with only ASCII characters so it is
+// safe in a header.
+func headerJSON(v interface{}) string {
+	// There is no way for marshal to fail since the flashes are static types
+	js, _ := json.Marshal(v)
+
+	var b strings.Builder
+	for _, c := range string(js) {
+		if c < 0x80 {
+			b.WriteRune(c)
+		} else if c > 0xFFFF {
+			// Use a surrogate pair
+			c -= 0x10000
+			fmt.Fprintf(&b, `\u%04x\u%04x`, 0xD800+(c>>10), 0xDC00+(c&0x3FF))
+		} else {
+			fmt.Fprintf(&b, `\u%04x`, c)
+		}
+	}
+
+	return b.String()
+}
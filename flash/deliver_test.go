@@ -0,0 +1,181 @@
+package flash_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/blue-jay/core/flash"
+	"github.com/blue-jay/core/session"
+
+	"github.com/gorilla/sessions"
+)
+
+// newSession returns a session config with the memory store.
+func newSession(t *testing.T) *session.Info {
+	s := &session.Info{
+		AuthKey: "PzCh6FNAB7/jhmlUQ0+25sjJ+WgcJeKR2bAOtnh9UnfVN+WJSBvY/YC80Rs+rbMtwfmSP4FUSxKPtpYKzKFqFA==",
+		Name:    "sess",
+		Store:   session.StoreMemory,
+		Options: sessions.Options{Path: "/", MaxAge: 28800},
+	}
+	if err := s.SetupConfig(); err != nil {
+		t.Fatal(err)
+	}
+
+	return s
+}
+
+// deliver adds a flash in the handler and returns the response.
+func deliver(s *session.Info, header string, value string, h http.HandlerFunc) *http.Response {
+	w := httptest.NewRecorder()
+	r, _ := http.NewRequest("GET", "/", nil)
+	if len(header) > 0 {
+		r.Header.Set(header, value)
+	}
+
+	s.Middleware(flash.Deliver(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		session.Current(r).AddFlash(flash.Message{Message: "Saved café.", Class: flash.Success})
+		h(w, r)
+	}))).ServeHTTP(w, r)
+
+	return w.Result()
+}
+
+// TestDeliver ensures the flashes are in the header for Ajax requests.
+func TestDeliver(t *testing.T) {
+	s := newSession(t)
+	defer s.Close()
+
+	res := deliver(s, "X-Requested-With", "XMLHttpRequest", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("partial"))
+	})
+
+	value := res.Header.Get(flash.Header)
+	expected := `[{"Message":"Saved caf\u00e9.","Class":"alert-success","Level":"success"}]`
+	if value != expected {
+		t.Fatalf("\nactual: %v\nexpected: %v", value, expected)
+	}
+
+	var flashes []flash.Message
+	if err := json.Unmarshal([]byte(value), &flashes); err != nil {
+		t.Fatal(err)
+	} else if flashes[0].Message != "Saved café." {
+		t.Fatalf("Message is wrong: %v", flashes[0].Message)
+	}
+}
+
+// TestDeliverHTMX ensures the flashes are added to the HX-Trigger events.
+func TestDeliverHTMX(t *testing.T) {
+	s := newSession(t)
+	defer s.Close()
+
+	res := deliver(s, "HX-Request", "true", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("HX-Trigger", "refresh")
+		w.WriteHeader(http.StatusOK)
+	})
+
+	events := make(map[string]json.RawMessage)
+	if err := json.Unmarshal([]byte(res.Header.Get("HX-Trigger")), &events); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := events["refresh"]; !ok {
+		t.Fatal("Event from the handler is missing.")
+	} else if _, ok := events[flash.HTMXEvent]; !ok {
+		t.Fatal("Flash event is missing.")
+	}
+}
+
+// TestDeliverKeep ensures the flashes are kept for full pages and redirects.
+func TestDeliverKeep(t *testing.T) {
+	s := newSession(t)
+	defer s.Close()
+
+	res := deliver(s, "", "", func(w http.ResponseWriter, r *http.Request) {
+		if len(session.Current(r).Flashes()) == 0 {
+			t.Fatal("Flashes should be kept for the view.")
+		}
+	})
+
+	if len(res.Header.Get(flash.Header)) > 0 {
+		t.Fatal("Header should not be set for a full page.")
+	}
+
+	res = deliver(s, "HX-Request", "true", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/", http.StatusFound)
+	})
+
+	if len(res.Header.Get("HX-Trigger")) > 0 {
+		t.Fatal("Header should not be set for a redirect.")
+	}
+}
+
+// TestSendJSON ensures the flashes are in the envelope.
+func TestSendJSON(t *testing.T) {
+	sess := &Session{}
+	sess.AddFlash(flash.Info{"Saved.", flash.Success})
+
+	w := httptest.NewRecorder()
+	r, _ := http.NewRequest("GET", "/", nil)
+
+	if err := flash.SendJSON(w, r, sess, http.StatusCreated, map[string]int{"ID": 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `{"Data":{"ID":1},"Flashes":[{"Message":"Saved.","Class":"alert-success","Level":"success"}]}`
+	if actual := w.Body.String(); actual != expected {
+		t.Fatalf("\nactual: %v\nexpected: %v", actual, expected)
+	} else if w.Code != http.StatusCreated {
+		t.Fatalf("Status should be %v, got: %v", http.StatusCreated, w.Code)
+	}
+
+	// No flashes is an empty list
+	w = httptest.NewRecorder()
+	flash.SendJSON(w, r, sess, http.StatusOK, nil)
+
+	if actual := w.Body.String(); actual != `{"Data":null,"Flashes":[]}` {
+		t.Fatalf("Flashes should be empty: %v", actual)
+	}
+}
+
+// TestDeliverHijack ensures Hijack reaches the connection and the flashes are
+// kept in the session.
+func TestDeliverHijack(t *testing.T) {
+	s := newSession(t)
+	defer s.Close()
+
+	ts := httptest.NewServer(s.Middleware(flash.Deliver(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		session.Current(r).AddFlash(flash.Message{Message: "Saved.", Class: flash.Success})
+
+		conn, buf, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer conn.Close()
+
+		// The header with the flashes is never sent
+		if f := session.Current(r).Flashes(); len(f) != 1 {
+			t.Errorf("Expected 1 flash, got: %v", len(f))
+		}
+
+		buf.WriteString("HTTP/1.1 204 No Content\r\n\r\n")
+		buf.Flush()
+	}))))
+	defer ts.Close()
+
+	r, _ := http.NewRequest("GET", ts.URL, nil)
+	r.Header.Set("X-Requested-With", "XMLHttpRequest")
+
+	res, err := http.DefaultClient.Do(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+
+	if res.StatusCode != http.StatusNoContent {
+		t.Fatalf("Status should be %v, got: %v", http.StatusNoContent, res.StatusCode)
+	}
+}
@@ -1,30 +1,38 @@
 // Package flash provides one-time messages for the user.
+//
+// The levels are semantic and are mapped to CSS classes by the theme from
+// SetConfig when the flashes are read with PeekFlashes or SendFlashes.
+//
+// To show the flashes in every view, add Modify with view.Info.SetModifiers
+// and Map with view.Info.SetFuncMaps, then use {{FLASHES .flashes}} in the
+// base template.
 package flash
 
 import (
 	"encoding/gob"
 	"encoding/json"
-	"fmt"
 	"net/http"
 )
 
+// The levels are variables so an app can still set its own. A level that is
+// not in the theme is used as the class.
 var (
-	// Error is a bootstrap class
-	Error = "alert-danger"
-	// Success is a bootstrap class
-	Success = "alert-success"
-	// Notice is a bootstrap class
-	Notice = "alert-info"
-	// Warning is a bootstrap class
-	Warning = "alert-warning"
-	// Standard is the default class
-	Standard = "alert-box"
+	// Error is the level for a failure
+	Error = "error"
+	// Success is the level for a completed action
+	Success = "success"
+	// Notice is the level for information
+	Notice = "info"
+	// Warning is the level for a possible problem
+	Warning = "warning"
+	// Standard is the default level
+	Standard = "standard"
 )
 
 // Info Flash Message
 type Info struct {
 	Message string
-	Class   string
+	Class   string // Level or class, it is replaced by the class from the theme
 }
 
 // Session is an interface for typical sessions
@@ -38,12 +46,13 @@ func init() {
 	// http://golang.org/pkg/encoding/gob/#Register
 	// Source: http://stackoverflow.com/questions/21934730/gob-type-not-registered-for-interface-mapstringinterface
 	gob.Register(Info{})
+	gob.Register(Message{})
 }
 
 // SendFlashes allows retrieval of flash messages for using with Ajax.
 func SendFlashes(w http.ResponseWriter, r *http.Request, sess Session) {
-	flashes := PeekFlashes(w, r, sess)
-	sess.Save(r, w)
+	flashes := Messages(w, r, sess)
+	save(w, r, sess)
 
 	// There is no way for marshal to fail since it's a static type
 	js, _ := json.Marshal(flashes)
@@ -60,13 +69,8 @@ func PeekFlashes(w http.ResponseWriter, r *http.Request, sess Session) []Info {
 	if flashes := sess.Flashes(); len(flashes) > 0 {
 		v = make([]Info, len(flashes))
 		for i, f := range flashes {
-			switch f.(type) {
-			case Info:
-				v[i] = f.(Info)
-			default:
-				v[i] = Info{fmt.Sprint(f), Standard}
-			}
-
+			m := message(f)
+			v[i] = Info{m.Message, m.Class}
 		}
 	}
 
@@ -113,7 +113,8 @@ func TestSendFlashes(t *testing.T) {
 	handler.ServeHTTP(w, r)
 
 	actual := w.Body.String()
-	expected := fmt.Sprintf(`[{"Message":"%v","Class":"%v"},{"Message":"%v","Class":"%v"}]`, text, flash.Success, text, flash.Standard)
+	expected := fmt.Sprintf(`[{"Message":"%v","Class":"%v","Level":"%v"},{"Message":"%v","Class":"%v","Level":"%v"}]`,
+		text, "alert-success", flash.Success, text, "alert-box", flash.Standard)
 
 	if actual != expected {
 		t.Fatalf("\nactual: %v\nexpected: %v", actual, expected)
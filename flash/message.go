@@ -0,0 +1,124 @@
+package flash
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"net/http"
+	"strings"
+)
+
+var (
+	// Translate returns the text for the translation key of a Message. When
+	// it is set, the Message text is replaced when the flashes are read.
+	Translate func(key string, args ...interface{}) string
+
+	// messageTemplate escapes the user-supplied text of a flash
+	messageTemplate = template.Must(template.New("flash").Parse(
+		`<div class="{{.Class}}" role="alert">` +
+			`{{if .Close}}<button type="button" class="{{.Close}}" data-dismiss="alert" data-bs-dismiss="alert" aria-label="Close"><span aria-hidden="true">&times;</span></button>{{end}}` +
+			`{{with .Message.Title}}<strong>{{.}}</strong> {{end}}` +
+			`{{.Message.Message}}` +
+			`{{with .Message.Action}} <a href="{{.URL}}">{{.Label}}</a>{{end}}` +
+			`</div>`))
+)
+
+// Action is a link shown with the flash like Undo.
+type Action struct {
+	Label string `json:"Label"`
+	URL   string `json:"URL"`
+}
+
+// Message is a flash with a title, an action link, and a translation key.
+type Message struct {
+	Message     string        `json:"Message"`
+	Class       string        `json:"Class"`                 // Level or class, it is replaced by the class from the theme
+	Level       string        `json:"Level,omitempty"`       // Level or class when the flash was added
+	Title       string        `json:"Title,omitempty"`       // Shown before the message
+	Action      *Action       `json:"Action,omitempty"`      // Link shown after the message
+	Dismissible bool          `json:"Dismissible,omitempty"` // Shows a button to close the flash
+	Key         string        `json:"Key,omitempty"`         // Translation key for the message
+	Args        []interface{} `json:"Args,omitempty"`        // Arguments for the translation key
+}
+
+// Messages returns the flashes and removes them from the session. The class
+// from the theme is set for each flash.
+func Messages(w http.ResponseWriter, r *http.Request, sess Session) []Message {
+	var v []Message
+
+	if flashes := sess.Flashes(); len(flashes) > 0 {
+		v = make([]Message, len(flashes))
+		for i, f := range flashes {
+			v[i] = message(f)
+		}
+	}
+
+	return v
+}
+
+// message converts a flash from the session to a Message.
+func message(f interface{}) Message {
+	var m Message
+
+	switch t := f.(type) {
+	case Message:
+		m = t
+	case Info:
+		m = Message{Message: t.Message, Class: t.Class}
+	default:
+		m = Message{Message: fmt.Sprint(f), Class: Standard}
+	}
+
+	if len(m.Class) == 0 {
+		m.Class = Standard
+	}
+
+	m.Level = m.Class
+	m.Class = Class(m.Level)
+
+	if len(m.Key) > 0 && Translate != nil {
+		m.Message = Translate(m.Key, m.Args...)
+	}
+
+	return m
+}
+
+// HTML returns the flash as HTML with the text escaped. The Message should be
+// from Messages so the class from the theme is set.
+func HTML(m Message) template.HTML {
+	t := CurrentTheme()
+
+	data := struct {
+		Message Message
+		Class   string
+		Close   string
+	}{
+		Message: m,
+		Class:   strings.TrimSpace(t.Base + " " + m.Class),
+	}
+
+	if m.Dismissible {
+		data.Close = t.Close
+		if len(data.Close) == 0 {
+			data.Close = "close"
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := messageTemplate.Execute(&buf, data); err != nil {
+		return template.HTML(template.HTMLEscapeString(m.Message))
+	}
+
+	return template.HTML(buf.String())
+}
+
+// Map returns a template.FuncMap FLASH that renders a flash as HTML and
+// FLASHES that renders the flashes from Modify as HTML.
+func Map() template.FuncMap {
+	f := make(template.FuncMap)
+
+	f["FLASH"] = HTML
+	f["FLASHES"] = list
+
+	return f
+}
@@ -0,0 +1,98 @@
+package flash_test
+
+import (
+	"bytes"
+	"encoding/gob"
+	"fmt"
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/blue-jay/core/flash"
+)
+
+// TestMessageGob ensures a Message can be stored in the cookie store.
+func TestMessageGob(t *testing.T) {
+	in := map[interface{}]interface{}{
+		"flash": flash.Message{
+			Message: "Deleted.",
+			Class:   flash.Success,
+			Action:  &flash.Action{Label: "Undo", URL: "/undo"},
+			Args:    []interface{}{"note", 1},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(in); err != nil {
+		t.Fatal(err)
+	}
+
+	out := make(map[interface{}]interface{})
+	if err := gob.NewDecoder(&buf).Decode(&out); err != nil {
+		t.Fatal(err)
+	}
+
+	m := out["flash"].(flash.Message)
+	if m.Action == nil || m.Action.Label != "Undo" || len(m.Args) != 2 {
+		t.Fatalf("Message is not the same: %+v", m)
+	}
+}
+
+// TestMessages ensures the rich fields are sent as JSON and translated.
+func TestMessages(t *testing.T) {
+	flash.Translate = func(key string, args ...interface{}) string {
+		return fmt.Sprintf("%v %v", key, args)
+	}
+	defer func() { flash.Translate = nil }()
+
+	sess := &Session{}
+	sess.AddFlash(flash.Message{
+		Class:       flash.Warning,
+		Title:       "Careful",
+		Dismissible: true,
+		Key:         "item.deleted",
+		Args:        []interface{}{"note"},
+	})
+
+	w := httptest.NewRecorder()
+	r, _ := http.NewRequest("GET", "/", nil)
+
+	flash.SendFlashes(w, r, sess)
+
+	expected := `[{"Message":"item.deleted [note]","Class":"alert-warning","Level":"warning","Title":"Careful","Dismissible":true,"Key":"item.deleted","Args":["note"]}]`
+	if actual := w.Body.String(); actual != expected {
+		t.Fatalf("\nactual: %v\nexpected: %v", actual, expected)
+	}
+}
+
+// TestHTML ensures the user-supplied text is escaped.
+func TestHTML(t *testing.T) {
+	sess := &Session{}
+	sess.AddFlash(flash.Message{
+		Message:     `<script>alert("x")</script>`,
+		Class:       flash.Error,
+		Title:       "<b>Oops</b>",
+		Action:      &flash.Action{Label: "Undo", URL: "javascript:alert(1)"},
+		Dismissible: true,
+	})
+
+	w := httptest.NewRecorder()
+	r, _ := http.NewRequest("GET", "/", nil)
+
+	m := flash.Messages(w, r, sess)[0]
+	html := string(flash.Map()["FLASH"].(func(flash.Message) template.HTML)(m))
+
+	for _, s := range []string{"<script>", "<b>", "javascript:"} {
+		if strings.Contains(html, s) {
+			t.Fatalf("HTML should be escaped: %v", html)
+		}
+	}
+
+	for _, s := range []string{`class="alert alert-danger"`, `class="close"`, "&lt;b&gt;Oops&lt;/b&gt;", ">Undo</a>"} {
+		if !strings.Contains(html, s) {
+			t.Fatalf("HTML should contain %v: %v", s, html)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package flash
+
+import (
+	"html/template"
+	"net/http"
+
+	"github.com/blue-jay/core/session"
+	"github.com/blue-jay/core/view"
+)
+
+// VarsKey is the key in view.View.Vars for the flashes.
+var VarsKey = "flashes"
+
+// Modify sets the flashes from the session in the view and removes them from
+// the session. It requires the session.Middleware which saves the session.
+// Use it with view.Info.SetModifiers.
+func Modify(w http.ResponseWriter, r *http.Request, v *view.View) {
+	sess := session.Current(r)
+	if sess == nil {
+		return
+	}
+
+	if flashes := Messages(w, r, sess); len(flashes) > 0 {
+		v.Vars[VarsKey] = flashes
+	}
+}
+
+// list returns the flashes as HTML. It accepts the flashes from Modify or
+// PeekFlashes.
+func list(flashes interface{}) template.HTML {
+	var html template.HTML
+
+	switch t := flashes.(type) {
+	case []Message:
+		for _, m := range t {
+			html += HTML(m)
+		}
+	case []Info:
+		for _, f := range t {
+			html += HTML(Message{Message: f.Message, Class: f.Class})
+		}
+	}
+
+	return html
+}
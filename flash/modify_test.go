@@ -0,0 +1,70 @@
+package flash_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/blue-jay/core/flash"
+	"github.com/blue-jay/core/session"
+	"github.com/blue-jay/core/view"
+
+	"github.com/gorilla/sessions"
+)
+
+// TestModify ensures the flashes are set in the view and rendered.
+func TestModify(t *testing.T) {
+	s := session.Info{
+		AuthKey: "PzCh6FNAB7/jhmlUQ0+25sjJ+WgcJeKR2bAOtnh9UnfVN+WJSBvY/YC80Rs+rbMtwfmSP4FUSxKPtpYKzKFqFA==",
+		Name:    "sess",
+		Store:   session.StoreMemory,
+		Options: sessions.Options{Path: "/", MaxAge: 28800},
+	}
+	if err := s.SetupConfig(); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	viewInfo := &view.Info{
+		BaseURI:   "/",
+		Extension: "tmpl",
+		Folder:    "testdata/view",
+		Caching:   true,
+	}
+	viewInfo.SetTemplates("flashes", []string{})
+	viewInfo.SetModifiers(flash.Modify)
+	viewInfo.SetFuncMaps(flash.Map())
+
+	// Add a flash and redirect
+	w := httptest.NewRecorder()
+	r, _ := http.NewRequest("POST", "/", nil)
+	s.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		session.Current(r).AddFlash(flash.Message{Message: "Saved <b>note</b>.", Class: flash.Success})
+		http.Redirect(w, r, "/", http.StatusFound)
+	})).ServeHTTP(w, r)
+
+	cookie := w.Result().Cookies()[0]
+
+	// Render the next page
+	render := func() string {
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/", nil)
+		r.AddCookie(cookie)
+		s.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if err := viewInfo.New().Render(w, r); err != nil {
+				t.Fatal(err)
+			}
+		})).ServeHTTP(w, r)
+		return w.Body.String()
+	}
+
+	expected := `<main><div class="alert alert-success" role="alert">Saved &lt;b&gt;note&lt;/b&gt;.</div></main>`
+	if actual := render(); actual != expected {
+		t.Fatalf("\nactual: %v\nexpected: %v", actual, expected)
+	}
+
+	// The flash is only shown once
+	if actual := render(); actual != "<main></main>" {
+		t.Fatalf("Flash should be removed: %v", actual)
+	}
+}
@@ -0,0 +1,141 @@
+package flash
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Theme maps the flash levels to CSS classes.
+type Theme struct {
+	Base    string            `json:"Base"`    // Class for every flash
+	Close   string            `json:"Close"`   // Class for the close button of a dismissible flash
+	Classes map[string]string `json:"Classes"` // Class for each level
+}
+
+// Config selects the theme and overrides the classes.
+type Config struct {
+	Theme   string            `json:"Theme" jsonconfig:"oneof=bootstrap3 bootstrap4 bootstrap5 tailwind bulma custom"` // Name of the theme in Themes, defaults to bootstrap3
+	Base    string            `json:"Base"`                                                                            // Overrides the class for every flash
+	Close   string            `json:"Close"`                                                                           // Overrides the class for the close button
+	Classes map[string]string `json:"Classes"`                                                                         // Overrides the class for each level
+}
+
+var (
+	// Themes are the built-in themes for the CSS frameworks
+	Themes = map[string]Theme{
+		"bootstrap3": {
+			Base:  "alert",
+			Close: "close",
+			Classes: map[string]string{
+				Error:    "alert-danger",
+				Success:  "alert-success",
+				Notice:   "alert-info",
+				Warning:  "alert-warning",
+				Standard: "alert-box",
+			},
+		},
+		"bootstrap4": {
+			Base:  "alert",
+			Close: "close",
+			Classes: map[string]string{
+				Error:    "alert-danger",
+				Success:  "alert-success",
+				Notice:   "alert-info",
+				Warning:  "alert-warning",
+				Standard: "alert-secondary",
+			},
+		},
+		"bootstrap5": {
+			Base:  "alert",
+			Close: "btn-close",
+			Classes: map[string]string{
+				Error:    "alert-danger",
+				Success:  "alert-success",
+				Notice:   "alert-info",
+				Warning:  "alert-warning",
+				Standard: "alert-secondary",
+			},
+		},
+		"tailwind": {
+			Base:  "border px-4 py-3 rounded",
+			Close: "float-right",
+			Classes: map[string]string{
+				Error:    "bg-red-100 border-red-400 text-red-700",
+				Success:  "bg-green-100 border-green-400 text-green-700",
+				Notice:   "bg-blue-100 border-blue-400 text-blue-700",
+				Warning:  "bg-yellow-100 border-yellow-400 text-yellow-700",
+				Standard: "bg-gray-100 border-gray-400 text-gray-700",
+			},
+		},
+		"bulma": {
+			Base:  "notification",
+			Close: "delete",
+			Classes: map[string]string{
+				Error:    "is-danger",
+				Success:  "is-success",
+				Notice:   "is-info",
+				Warning:  "is-warning",
+				Standard: "is-light",
+			},
+		},
+	}
+
+	theme      = Themes["bootstrap3"]
+	themeMutex sync.RWMutex
+)
+
+// SetConfig sets the theme used when flashes are rendered. The custom theme
+// starts empty so every class must be set in the config.
+func SetConfig(c Config) error {
+	name := c.Theme
+	if len(name) == 0 {
+		name = "bootstrap3"
+	}
+
+	t, ok := Themes[name]
+	if !ok && name != "custom" {
+		return fmt.Errorf("Flash theme is not supported: %v", name)
+	}
+
+	// Copy the classes so the built-in theme is not changed
+	classes := make(map[string]string)
+	for k, v := range t.Classes {
+		classes[k] = v
+	}
+	for k, v := range c.Classes {
+		classes[k] = v
+	}
+
+	if len(c.Base) > 0 {
+		t.Base = c.Base
+	}
+	if len(c.Close) > 0 {
+		t.Close = c.Close
+	}
+	t.Classes = classes
+
+	themeMutex.Lock()
+	theme = t
+	themeMutex.Unlock()
+
+	return nil
+}
+
+// CurrentTheme returns the theme from SetConfig.
+func CurrentTheme() Theme {
+	themeMutex.RLock()
+	t := theme
+	themeMutex.RUnlock()
+
+	return t
+}
+
+// Class returns the class for the level. A level without a class in the theme
+// is returned as is so a class can be used instead of a level.
+func Class(level string) string {
+	if class, ok := CurrentTheme().Classes[level]; ok {
+		return class
+	}
+
+	return level
+}
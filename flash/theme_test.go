@@ -0,0 +1,74 @@
+package flash_test
+
+import (
+	"testing"
+
+	"github.com/blue-jay/core/flash"
+)
+
+// TestTheme ensures the levels are mapped to the classes of the theme.
+func TestTheme(t *testing.T) {
+	defer flash.SetConfig(flash.Config{})
+
+	if err := flash.SetConfig(flash.Config{Theme: "bulma"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if c := flash.Class(flash.Error); c != "is-danger" {
+		t.Fatalf("Class should be is-danger, got: %v", c)
+	} else if b := flash.CurrentTheme().Base; b != "notification" {
+		t.Fatalf("Base should be notification, got: %v", b)
+	}
+
+	// A class that is not a level is kept
+	if c := flash.Class("my-class"); c != "my-class" {
+		t.Fatalf("Class should be my-class, got: %v", c)
+	}
+
+	// Override a class
+	if err := flash.SetConfig(flash.Config{Theme: "bulma", Classes: map[string]string{flash.Error: "is-error"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	if c := flash.Class(flash.Error); c != "is-error" {
+		t.Fatalf("Class should be is-error, got: %v", c)
+	} else if c := flash.Class(flash.Success); c != "is-success" {
+		t.Fatalf("Class should be is-success, got: %v", c)
+	} else if c := flash.Themes["bulma"].Classes[flash.Error]; c != "is-danger" {
+		t.Fatalf("Built-in theme should not change, got: %v", c)
+	}
+
+	// Custom theme
+	if err := flash.SetConfig(flash.Config{Theme: "custom", Base: "msg", Classes: map[string]string{flash.Notice: "msg-info"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	if c := flash.Class(flash.Notice); c != "msg-info" {
+		t.Fatalf("Class should be msg-info, got: %v", c)
+	} else if c := flash.Class(flash.Error); c != flash.Error {
+		t.Fatalf("Class should be %v, got: %v", flash.Error, c)
+	}
+}
+
+// TestThemeUnsupported ensures an unknown theme is an error.
+func TestThemeUnsupported(t *testing.T) {
+	if err := flash.SetConfig(flash.Config{Theme: "foundation"}); err == nil {
+		t.Fatal("Expected an error for an unknown theme.")
+	}
+
+	if c := flash.Class(flash.Success); c != "alert-success" {
+		t.Fatalf("Theme should not change, got: %v", c)
+	}
+}
+
+// TestThemeAssigned ensures a level set by the app is used as the class like
+// before the themes.
+func TestThemeAssigned(t *testing.T) {
+	old := flash.Error
+	flash.Error = "alert-danger"
+	defer func() { flash.Error = old }()
+
+	if c := flash.Class(flash.Error); c != "alert-danger" {
+		t.Fatalf("Class should be alert-danger, got: %v", c)
+	}
+}
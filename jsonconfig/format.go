@@ -0,0 +1,67 @@
+package jsonconfig
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v2"
+)
+
+// toJSON converts the file contents to JSON based on the file extension so
+// the same json struct tags are used for every format. Files with the
+// extension .yaml or .yml are read as YAML, files with the extension .toml
+// are read as TOML, and all other files are read as JSON.
+func toJSON(file string, data []byte) ([]byte, error) {
+	var v interface{}
+
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".yaml", ".yml":
+		if err := yaml.Unmarshal(data, &v); err != nil {
+			return nil, err
+		}
+	case ".toml":
+		m := make(map[string]interface{})
+		if _, err := toml.Decode(string(data), &m); err != nil {
+			return nil, err
+		}
+		v = m
+	default:
+		return data, nil
+	}
+
+	return json.Marshal(normalize(v))
+}
+
+// normalize converts the maps from the decoders to maps with string keys so
+// they can be marshaled to JSON.
+func normalize(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(t))
+		for k, val := range t {
+			m[fmt.Sprint(k)] = normalize(val)
+		}
+		return m
+	case map[string]interface{}:
+		for k, val := range t {
+			t[k] = normalize(val)
+		}
+		return t
+	case []interface{}:
+		for i, val := range t {
+			t[i] = normalize(val)
+		}
+		return t
+	case []map[string]interface{}:
+		list := make([]interface{}, len(t))
+		for i, val := range t {
+			list[i] = normalize(val)
+		}
+		return list
+	}
+
+	return v
+}
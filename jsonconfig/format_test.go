@@ -0,0 +1,57 @@
+package jsonconfig_test
+
+import (
+	"testing"
+
+	"github.com/blue-jay/core/jsonconfig"
+)
+
+// TestLoadFormat ensures YAML and TOML files load properly.
+func TestLoadFormat(t *testing.T) {
+	tests := map[string]string{
+		"testdata/envtest.yaml": "yamlfolder",
+		"testdata/envtest.toml": "tomlfolder",
+	}
+
+	for file, expected := range tests {
+		i := &Info{}
+
+		err := jsonconfig.LoadProfile(file, "", i)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		received := i.Asset.Folder
+
+		if expected != received {
+			t.Errorf("%v\n got: %v\nwant: %v", file, received, expected)
+		}
+	}
+}
+
+// TestLoadFormatProfile ensures a YAML profile is merged.
+func TestLoadFormatProfile(t *testing.T) {
+	i := &Info{}
+
+	err := jsonconfig.LoadProfile("testdata/envtest.yaml", "production", i)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "yamlprodfolder"
+	received := i.Asset.Folder
+
+	if expected != received {
+		t.Errorf("\n got: %v\nwant: %v", received, expected)
+	}
+}
+
+// TestLoadFormatMalformed ensures the file fails on malformed YAML.
+func TestLoadFormatMalformed(t *testing.T) {
+	i := &Info{}
+
+	err := jsonconfig.LoadProfile("testdata/envmalformed.yaml", "", i)
+	if err == nil {
+		t.Fatal("Load should have failed on malformed yaml")
+	}
+}
@@ -0,0 +1,170 @@
+package jsonconfig
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Interpolate replaces the variables in every string value of the JSON
+// document and returns the new document. The document is returned unchanged if
+// it does not contain any variables.
+//
+// Variables:
+//
+//	${VAR}                  value of the environment variable VAR
+//	${VAR:-default}         value of VAR or default if VAR is unset or empty
+//	${.Server.Hostname}     value of another key in the document
+//	${.Server.Port:-80}     value of another key or default if it is missing
+//	$${VAR}                 literal ${VAR}
+//
+// An error is returned with the name of the variable and the JSON path where
+// it was used if a variable is not set and has no default.
+func Interpolate(data []byte) ([]byte, error) {
+	if !bytes.Contains(data, []byte("${")) {
+		return data, nil
+	}
+
+	root, err := decode(data)
+	if err != nil {
+		return nil, err
+	}
+
+	// References are resolved against an unchanged copy of the document so a
+	// value is never expanded twice
+	doc, err := decode(data)
+	if err != nil {
+		return nil, err
+	}
+
+	in := &interpolator{
+		root:    root,
+		visited: make(map[string]bool),
+	}
+
+	v, err := walkStrings("", doc, in.expand)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(v)
+}
+
+// interpolator holds the original document so keys can reference each other.
+type interpolator struct {
+	root    interface{}
+	visited map[string]bool
+}
+
+// expand replaces the variables in the string found at the path.
+func (in *interpolator) expand(path string, s string) (string, error) {
+	var out strings.Builder
+
+	for {
+		i := strings.Index(s, "${")
+		if i < 0 {
+			out.WriteString(s)
+			break
+		}
+
+		// Write out an escaped variable as is
+		if i > 0 && s[i-1] == '$' {
+			out.WriteString(s[:i-1] + "${")
+			s = s[i+2:]
+			continue
+		}
+
+		end := strings.Index(s[i:], "}")
+		if end < 0 {
+			return "", fmt.Errorf("Variable is missing a closing brace in %v: %v", path, s[i:])
+		}
+
+		val, err := in.resolve(path, s[i+2:i+end])
+		if err != nil {
+			return "", err
+		}
+
+		out.WriteString(s[:i] + val)
+		s = s[i+end+1:]
+	}
+
+	return out.String(), nil
+}
+
+// resolve returns the value of the environment variable or key.
+func (in *interpolator) resolve(path string, expr string) (string, error) {
+	name, def, hasDef := expr, "", false
+	if i := strings.Index(expr, ":-"); i >= 0 {
+		name, def, hasDef = expr[:i], expr[i+2:], true
+	}
+
+	// Reference to another key
+	if strings.HasPrefix(name, ".") {
+		key := strings.TrimPrefix(name, ".")
+
+		v, ok := lookup(in.root, key)
+		if !ok {
+			if hasDef {
+				return def, nil
+			}
+			return "", fmt.Errorf("Config key %v does not exist: used in %v", key, path)
+		}
+
+		s, ok := v.(string)
+		if !ok {
+			return fmt.Sprint(v), nil
+		}
+
+		// Expand the variables in the referenced key
+		if in.visited[key] {
+			return "", fmt.Errorf("Config key %v references itself: used in %v", key, path)
+		}
+		in.visited[key] = true
+		defer delete(in.visited, key)
+
+		return in.expand(key, s)
+	}
+
+	// Environment variable
+	if v := os.Getenv(name); len(v) > 0 {
+		return v, nil
+	}
+
+	if hasDef {
+		return def, nil
+	}
+
+	if _, ok := os.LookupEnv(name); ok {
+		return "", nil
+	}
+
+	return "", fmt.Errorf("Environment variable %v is not set: used in %v", name, path)
+}
+
+// lookup returns the value at the dot separated path. Array elements are
+// referenced by index like List.0.Name.
+func lookup(v interface{}, path string) (interface{}, bool) {
+	for _, key := range strings.Split(path, ".") {
+		switch t := v.(type) {
+		case map[string]interface{}:
+			val, ok := t[key]
+			if !ok {
+				return nil, false
+			}
+			v = val
+		case []interface{}:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(t) {
+				return nil, false
+			}
+			v = t[i]
+		default:
+			return nil, false
+		}
+	}
+
+	return v, true
+}
@@ -0,0 +1,127 @@
+package jsonconfig_test
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/blue-jay/core/jsonconfig"
+)
+
+// TestInterpolate ensures variables are replaced.
+func TestInterpolate(t *testing.T) {
+	os.Setenv("TEST_HOST", "db.example.com")
+	os.Setenv("TEST_EMPTY", "")
+	defer os.Clearenv()
+
+	data := []byte(`{
+		"Server":{"Hostname":"${TEST_HOST}","Port":8080},
+		"MySQL":{"Hostname":"${.Server.Hostname}","Database":"${TEST_DB:-blueprint}"},
+		"BaseURI":"http://${.MySQL.Hostname}:${.Server.Port}/",
+		"Empty":"${TEST_EMPTY:-default}",
+		"List":["${.Server.Hostname}"],
+		"Literal":"$${TEST_HOST}"
+	}`)
+
+	b, err := jsonconfig.Interpolate(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var received, expected interface{}
+	received, _ = decodeJSON(b)
+	expected, _ = decodeJSON([]byte(`{
+		"Server":{"Hostname":"db.example.com","Port":8080},
+		"MySQL":{"Hostname":"db.example.com","Database":"blueprint"},
+		"BaseURI":"http://db.example.com:8080/",
+		"Empty":"default",
+		"List":["db.example.com"],
+		"Literal":"${TEST_HOST}"
+	}`))
+
+	if !jsonEqual(received, expected) {
+		t.Errorf("\n got: %s\nwant: %v", b, expected)
+	}
+}
+
+// TestInterpolateEscaped ensures a reference to an escaped variable is not
+// expanded again no matter which key is expanded first.
+func TestInterpolateEscaped(t *testing.T) {
+	os.Setenv("ZZ", "v")
+	defer os.Clearenv()
+
+	data := []byte(`{"a":"$${ZZ}","b":"${.a}","c":"${.b}"}`)
+
+	for i := 0; i < 100; i++ {
+		b, err := jsonconfig.Interpolate(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		received, _ := decodeJSON(b)
+		expected, _ := decodeJSON([]byte(`{"a":"${ZZ}","b":"${ZZ}","c":"${ZZ}"}`))
+
+		if !jsonEqual(received, expected) {
+			t.Fatalf("\n got: %s\nwant: %v", b, expected)
+		}
+	}
+}
+
+// TestInterpolateFail ensures the error names the variable and path.
+func TestInterpolateFail(t *testing.T) {
+	os.Clearenv()
+
+	tests := map[string]string{
+		`{"MySQL":{"Password":"${TEST_PASSWORD}"}}`: "TEST_PASSWORD is not set: used in MySQL.Password",
+		`{"MySQL":{"Hostname":"${.Server.Host}"}}`:  "Server.Host does not exist: used in MySQL.Hostname",
+		`{"A":"${.B}","B":"${.A}"}`:                 "references itself",
+		`{"A":"${TEST_HOST"}`:                       "missing a closing brace in A",
+	}
+
+	for data, expected := range tests {
+		_, err := jsonconfig.Interpolate([]byte(data))
+		if err == nil {
+			t.Errorf("Interpolate should have failed: %v", data)
+		} else if !strings.Contains(err.Error(), expected) {
+			t.Errorf("\n got: %v\nwant: %v", err, expected)
+		}
+	}
+}
+
+// TestLoadInterpolate ensures variables are replaced when loaded.
+func TestLoadInterpolate(t *testing.T) {
+	i := &Info{}
+
+	os.Setenv("TEST_FOLDER", "envfolder")
+	defer os.Clearenv()
+
+	err := jsonconfig.LoadProfile("testdata/envinterpolate.json", "", i)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "envfolder/asset"
+	received := i.Asset.Folder
+
+	if expected != received {
+		t.Errorf("\n got: %v\nwant: %v", received, expected)
+	}
+}
+
+// TestEffectiveVariables ensures the variables are not replaced in the
+// printed config so secrets in the environment are not shown.
+func TestEffectiveVariables(t *testing.T) {
+	os.Setenv("TEST_FOLDER", "envfolder")
+	defer os.Clearenv()
+
+	b, err := jsonconfig.Effective("testdata/envinterpolate.json", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Contains(string(b), "envfolder") {
+		t.Fatalf("Variable should not be replaced: %s", b)
+	} else if !strings.Contains(string(b), "${TEST_FOLDER}/asset") {
+		t.Fatalf("Variable is missing: %s", b)
+	}
+}
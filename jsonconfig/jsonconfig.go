@@ -1,10 +1,40 @@
 // Package jsonconfig handles loading a JSON file into a struct.
+//
+// YAML (.yaml or .yml) and TOML (.toml) files are converted to JSON before
+// they are passed to ParseJSON so the json struct tags are used for every
+// format. The format is detected by the file extension.
+//
+// A profile can be merged on top of the config file by setting the JAYPROFILE
+// environment variable. With JAYCONFIG set to env.json and JAYPROFILE set to
+// production, the file env.production.json is merged into env.json before it
+// is parsed. See Merge for the merge rules.
+//
+// Variables like ${VAR} in string values are replaced with environment
+// variables or other keys before the file is parsed. See Interpolate for the
+// syntax.
+//
+// String values that start with "enc:" are decrypted with the master key from
+// the JAYKEY or JAYKEYFILE environment variable so the file can be committed
+// without exposing credentials. See Seal and Unseal.
+//
+// After the file is parsed, any field can be overridden by an environment
+// variable so secrets do not need to be stored in the file. See Override for
+// the naming scheme.
+//
+// The struct is then checked against the rules in the jsonconfig struct tags
+// and every problem is returned at once. See Validate for the rules.
+//
+// A Watcher can reload the config when the file changes and notify
+// subscribers so settings can be swapped without a restart.
 package jsonconfig
 
 import (
 	"errors"
+	"fmt"
 	"io/ioutil"
 	"os"
+	"path/filepath"
+	"strings"
 )
 
 // Parser must implement ParseJSON.
@@ -12,10 +42,23 @@ type Parser interface {
 	ParseJSON([]byte) error
 }
 
-// Load the JSON config file.
+// Load the JSON config file merged with the profile from the JAYPROFILE
+// environment variable.
 func Load(configFile string, p Parser) error {
+	return LoadProfile(configFile, os.Getenv("JAYPROFILE"), p)
+}
+
+// LoadProfile loads the JSON config file merged with the profile files. The
+// profile can be a comma separated list and each profile is merged in order.
+func LoadProfile(configFile string, profile string, p Parser) error {
 	// Read the config file
-	jsonBytes, err := ioutil.ReadFile(configFile)
+	jsonBytes, err := read(configFile, profile)
+	if err != nil {
+		return err
+	}
+
+	// Decrypt the sealed values
+	jsonBytes, err = Unseal(jsonBytes)
 	if err != nil {
 		return err
 	}
@@ -25,7 +68,15 @@ func Load(configFile string, p Parser) error {
 		return err
 	}
 
-	return nil
+	// Override the config with environment variables
+	if len(EnvPrefix) > 0 {
+		if err := Override(EnvPrefix, p); err != nil {
+			return err
+		}
+	}
+
+	// Validate the config
+	return Validate(p)
 }
 
 // LoadFromEnv returns the storage configuration information from the JAYCONFIG
@@ -38,3 +89,81 @@ func LoadFromEnv(p Parser) error {
 
 	return Load(jc, p)
 }
+
+// Effective returns the config file merged with the profile files as indented
+// JSON so it can be printed. Variables are not replaced and sealed values are
+// left sealed so secrets are not printed. Environment variable overrides are
+// not included since they are applied to the struct after parsing.
+func Effective(configFile string, profile string) ([]byte, error) {
+	jsonBytes, err := mergeFiles(configFile, profile)
+	if err != nil {
+		return nil, err
+	}
+
+	// Merging with an empty document formats the output the same way whether
+	// there is a profile or not
+	return Merge(jsonBytes, []byte("{}"))
+}
+
+// ProfileFile returns the path to the profile file by adding the profile
+// before the extension: env.json becomes env.production.json.
+func ProfileFile(configFile string, profile string) string {
+	ext := filepath.Ext(configFile)
+	return strings.TrimSuffix(configFile, ext) + "." + profile + ext
+}
+
+// read returns the config file merged with the profile files with the
+// variables replaced. The sealed values are not decrypted.
+func read(configFile string, profile string) ([]byte, error) {
+	jsonBytes, err := mergeFiles(configFile, profile)
+	if err != nil {
+		return nil, err
+	}
+
+	// Replace the variables
+	return Interpolate(jsonBytes)
+}
+
+// mergeFiles returns the config file merged with the profile files.
+func mergeFiles(configFile string, profile string) ([]byte, error) {
+	jsonBytes, err := readFile(configFile)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, name := range strings.Split(profile, ",") {
+		name = strings.TrimSpace(name)
+		if len(name) == 0 {
+			continue
+		}
+
+		file := ProfileFile(configFile, name)
+
+		overlay, err := readFile(file)
+		if err != nil {
+			return nil, err
+		}
+
+		jsonBytes, err = Merge(jsonBytes, overlay)
+		if err != nil {
+			return nil, fmt.Errorf("%v: %v", file, err)
+		}
+	}
+
+	return jsonBytes, nil
+}
+
+// readFile returns the contents of the file converted to JSON.
+func readFile(file string) ([]byte, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	jsonBytes, err := toJSON(file, data)
+	if err != nil {
+		return nil, fmt.Errorf("%v: %v", file, err)
+	}
+
+	return jsonBytes, nil
+}
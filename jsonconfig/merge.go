@@ -0,0 +1,58 @@
+package jsonconfig
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// Merge returns the overlay JSON document merged on top of the base JSON
+// document as indented JSON. Objects are merged key by key at every level.
+// Any other value in the overlay, including arrays and null, replaces the
+// value in the base.
+func Merge(base []byte, overlay []byte) ([]byte, error) {
+	b, err := decode(base)
+	if err != nil {
+		return nil, err
+	}
+
+	o, err := decode(overlay)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.MarshalIndent(merge(b, o), "", "  ")
+}
+
+// decode unmarshals the JSON document without losing number precision.
+func decode(data []byte) (interface{}, error) {
+	var v interface{}
+
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+	err := d.Decode(&v)
+
+	return v, err
+}
+
+// merge returns the overlay value merged on top of the base value.
+func merge(base interface{}, overlay interface{}) interface{} {
+	b, ok := base.(map[string]interface{})
+	if !ok {
+		return overlay
+	}
+
+	o, ok := overlay.(map[string]interface{})
+	if !ok {
+		return overlay
+	}
+
+	for k, v := range o {
+		if bv, ok := b[k]; ok {
+			b[k] = merge(bv, v)
+		} else {
+			b[k] = v
+		}
+	}
+
+	return b
+}
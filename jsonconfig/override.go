@@ -0,0 +1,187 @@
+package jsonconfig
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// EnvPrefix is the prefix of the environment variables that override the
+// values in the config file. Set it to an empty string to disable overrides.
+var EnvPrefix = "JAY"
+
+// Override sets the fields of v from environment variables. The variable name
+// is the prefix followed by the JSON key of each field in the path, in
+// uppercase and separated by underscores. Characters that are not letters or
+// digits are replaced by underscores.
+//
+// Examples with the prefix JAY:
+//
+//	JAY_MYSQL_PASSWORD                 sets MySQL.Password
+//	JAY_MYSQL_MIGRATION_TABLE          sets MySQL.Migration.Table
+//	JAY_SESSION_AUTHKEY                sets Session.AuthKey
+//	JAY_SESSION_OPTIONS_MAXAGE         sets Session.Options.MaxAge
+//	JAY_CONNECTIONS_LEGACY_MYSQL_PORT  sets Connections["legacy"].MySQL.Port
+//
+// The JSON key is read from the json struct tag and falls back to the field
+// name. Fields of embedded structs without a tag are promoted like they are in
+// encoding/json. Map entries can only be overridden if the key already exists
+// in the map. Slices of strings are read as a comma separated list.
+func Override(prefix string, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return errors.New("Override requires a non-nil pointer.")
+	}
+
+	return override(prefix, rv.Elem())
+}
+
+// override walks the value and sets each field that has a matching
+// environment variable.
+func override(name string, v reflect.Value) error {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return nil
+		}
+		return override(name, v.Elem())
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+
+			// Skip unexported fields unless they are embedded
+			if len(f.PkgPath) > 0 && !f.Anonymous {
+				continue
+			}
+
+			key, promoted, ok := fieldKey(f)
+			if !ok {
+				continue
+			}
+
+			child := name
+			if !promoted {
+				child = joinEnv(name, key)
+			}
+
+			if err := override(child, v.Field(i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return nil
+		}
+		for _, k := range v.MapKeys() {
+			// Map values are not addressable so update a copy
+			e := reflect.New(v.Type().Elem()).Elem()
+			e.Set(v.MapIndex(k))
+			if err := override(joinEnv(name, k.String()), e); err != nil {
+				return err
+			}
+			v.SetMapIndex(k, e)
+		}
+	default:
+		s, ok := os.LookupEnv(name)
+		if !ok || !v.CanSet() {
+			return nil
+		}
+		if err := setString(v, s); err != nil {
+			return fmt.Errorf("Environment variable %v is invalid: %v", name, err)
+		}
+	}
+
+	return nil
+}
+
+// fieldKey returns the JSON key of the field, whether the fields of the field
+// are promoted to the parent, and false if the field should be skipped.
+func fieldKey(f reflect.StructField) (string, bool, bool) {
+	tag := strings.Split(f.Tag.Get("json"), ",")[0]
+	if tag == "-" {
+		return "", false, false
+	}
+
+	if len(tag) > 0 {
+		return tag, false, true
+	}
+
+	if f.Anonymous && f.Type.Kind() == reflect.Struct {
+		return "", true, true
+	}
+
+	return f.Name, false, true
+}
+
+// joinEnv appends the key to the environment variable name.
+func joinEnv(name, key string) string {
+	key = strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '_'
+	}, strings.ToUpper(key))
+
+	if len(name) == 0 {
+		return key
+	}
+
+	return name + "_" + key
+}
+
+// setString converts the string to the kind of the value and sets it.
+func setString(v reflect.Value, s string) error {
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		// Allow durations like 30s
+		if v.Type() == reflect.TypeOf(time.Duration(0)) {
+			if d, err := time.ParseDuration(s); err == nil {
+				v.SetInt(int64(d))
+				return nil
+			}
+		}
+		i, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		i, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(i)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	case reflect.Slice:
+		if v.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("type %v is not supported", v.Type())
+		}
+		list := strings.Split(s, ",")
+		for i := range list {
+			list[i] = strings.TrimSpace(list[i])
+		}
+		v.Set(reflect.ValueOf(list).Convert(v.Type()))
+	default:
+		return fmt.Errorf("type %v is not supported", v.Type())
+	}
+
+	return nil
+}
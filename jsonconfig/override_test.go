@@ -0,0 +1,124 @@
+package jsonconfig_test
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/blue-jay/core/jsonconfig"
+)
+
+// OverrideInfo is an example structure with nested values.
+type OverrideInfo struct {
+	MySQL       MySQLInfo            `json:"MySQL"`
+	Connections map[string]MySQLInfo `json:"Connections"`
+	Embedded
+	Timeout time.Duration
+	Hosts   []string
+	Skip    string `json:"-"`
+	secret  string
+}
+
+// MySQLInfo holds a test config.
+type MySQLInfo struct {
+	Password  string `json:"Password"`
+	Port      int    `json:"Port"`
+	Migration struct {
+		Table string
+	} `json:"Migration"`
+}
+
+// Embedded holds fields that are promoted.
+type Embedded struct {
+	Debug bool
+}
+
+// TestOverride ensures environment variables override the values.
+func TestOverride(t *testing.T) {
+	i := &OverrideInfo{
+		Connections: map[string]MySQLInfo{
+			"legacy": {Port: 3306},
+		},
+	}
+
+	os.Setenv("TEST_MYSQL_PASSWORD", "secret")
+	os.Setenv("TEST_MYSQL_PORT", "3307")
+	os.Setenv("TEST_MYSQL_MIGRATION_TABLE", "migration")
+	os.Setenv("TEST_CONNECTIONS_LEGACY_PORT", "3308")
+	os.Setenv("TEST_DEBUG", "true")
+	os.Setenv("TEST_TIMEOUT", "30s")
+	os.Setenv("TEST_HOSTS", "a, b")
+	os.Setenv("TEST_SKIP", "skip")
+	defer os.Clearenv()
+
+	err := jsonconfig.Override("TEST", i)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if i.MySQL.Password != "secret" || i.MySQL.Port != 3307 {
+		t.Errorf("MySQL was not overridden: %v", i.MySQL)
+	}
+
+	if i.MySQL.Migration.Table != "migration" {
+		t.Errorf("\n got: %v\nwant: %v", i.MySQL.Migration.Table, "migration")
+	}
+
+	if i.Connections["legacy"].Port != 3308 {
+		t.Errorf("\n got: %v\nwant: %v", i.Connections["legacy"].Port, 3308)
+	}
+
+	if !i.Debug {
+		t.Error("Embedded field was not overridden.")
+	}
+
+	if i.Timeout != 30*time.Second {
+		t.Errorf("\n got: %v\nwant: %v", i.Timeout, 30*time.Second)
+	}
+
+	if len(i.Hosts) != 2 || i.Hosts[1] != "b" {
+		t.Errorf("\n got: %v\nwant: %v", i.Hosts, []string{"a", "b"})
+	}
+
+	if len(i.Skip) > 0 {
+		t.Error("Field with a dash tag should be skipped.")
+	}
+}
+
+// TestOverrideFail ensures an invalid value fails.
+func TestOverrideFail(t *testing.T) {
+	i := &OverrideInfo{}
+
+	os.Setenv("TEST_MYSQL_PORT", "abc")
+	defer os.Clearenv()
+
+	err := jsonconfig.Override("TEST", i)
+	if err == nil {
+		t.Fatal("Override should have failed because the port is not a number.")
+	}
+
+	err = jsonconfig.Override("TEST", *i)
+	if err == nil {
+		t.Fatal("Override should have failed because it is not a pointer.")
+	}
+}
+
+// TestLoadOverride ensures the file is overridden when loaded.
+func TestLoadOverride(t *testing.T) {
+	i := &Info{}
+
+	os.Setenv("JAY_ASSET_FOLDER", "barfolder")
+	defer os.Clearenv()
+
+	err := jsonconfig.Load("testdata/envtest.json", i)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "barfolder"
+	received := i.Asset.Folder
+
+	if expected != received {
+		t.Errorf("\n got: %v\nwant: %v", received, expected)
+	}
+}
@@ -0,0 +1,102 @@
+package jsonconfig_test
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/blue-jay/core/jsonconfig"
+)
+
+// TestLoadProfile ensures the profile is merged on top of the file.
+func TestLoadProfile(t *testing.T) {
+	i := &Info{}
+
+	os.Setenv("JAYPROFILE", "production")
+	defer os.Clearenv()
+
+	err := jsonconfig.Load("testdata/envtest.json", i)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "prodfolder"
+	received := i.Asset.Folder
+
+	if expected != received {
+		t.Errorf("\n got: %v\nwant: %v", received, expected)
+	}
+}
+
+// TestLoadProfileFail ensures a missing or malformed profile fails.
+func TestLoadProfileFail(t *testing.T) {
+	i := &Info{}
+
+	err := jsonconfig.LoadProfile("testdata/envtest.json", "missing", i)
+	if err == nil {
+		t.Fatal("File should have failed because the profile is missing.")
+	}
+
+	err = jsonconfig.LoadProfile("testdata/envtest.json", "malformed", i)
+	if err == nil {
+		t.Fatal("File should have failed because the profile is malformed.")
+	}
+}
+
+// TestProfileFile ensures the profile is added before the extension.
+func TestProfileFile(t *testing.T) {
+	expected := "config/env.production.json"
+	received := jsonconfig.ProfileFile("config/env.json", "production")
+
+	if expected != received {
+		t.Errorf("\n got: %v\nwant: %v", received, expected)
+	}
+}
+
+// TestMerge ensures nested objects are merged and other values are replaced.
+func TestMerge(t *testing.T) {
+	base := []byte(`{"MySQL":{"Hostname":"localhost","Port":3306,"Migration":{"Table":"migration","Folder":"migration"}},"List":[1,2]}`)
+	overlay := []byte(`{"MySQL":{"Hostname":"db.example.com","Migration":{"Folder":"prod"}},"List":[3],"Extra":true}`)
+
+	b, err := jsonconfig.Merge(base, overlay)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var received, expected interface{}
+	json.Unmarshal(b, &received)
+	json.Unmarshal([]byte(`{"MySQL":{"Hostname":"db.example.com","Port":3306,"Migration":{"Table":"migration","Folder":"prod"}},"List":[3],"Extra":true}`), &expected)
+
+	if !jsonEqual(received, expected) {
+		t.Errorf("\n got: %s\nwant: %v", b, expected)
+	}
+}
+
+// TestEffective ensures the merged config can be printed.
+func TestEffective(t *testing.T) {
+	b, err := jsonconfig.Effective("testdata/envtest.json", "production")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "{\n  \"Asset\": {\n    \"Folder\": \"prodfolder\"\n  }\n}"
+	received := string(b)
+
+	if expected != received {
+		t.Errorf("\n got: %v\nwant: %v", received, expected)
+	}
+}
+
+// decodeJSON unmarshals the JSON document.
+func decodeJSON(b []byte) (interface{}, error) {
+	var v interface{}
+	err := json.Unmarshal(b, &v)
+	return v, err
+}
+
+// jsonEqual compares two decoded JSON documents.
+func jsonEqual(a, b interface{}) bool {
+	ja, _ := json.Marshal(a)
+	jb, _ := json.Marshal(b)
+	return string(ja) == string(jb)
+}
@@ -0,0 +1,174 @@
+package jsonconfig
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+// SealedPrefix is the prefix of a string value that is encrypted.
+const SealedPrefix = "enc:"
+
+var (
+	// ErrMasterKeyMissing is when a sealed value is found without a master key
+	ErrMasterKeyMissing = errors.New("Environment variable JAYKEY or JAYKEYFILE needs to be set to decrypt the config.")
+	// ErrMasterKeyInvalid is when the master key is not 32 bytes
+	ErrMasterKeyInvalid = errors.New("Master key must be a base64 encoded 32 byte key.")
+	// ErrSealedInvalid is when the sealed value cannot be decrypted
+	ErrSealedInvalid = errors.New("Sealed value cannot be decrypted.")
+)
+
+// MasterKey returns the base64 encoded master key from the JAYKEY environment
+// variable or from the file in the JAYKEYFILE environment variable.
+func MasterKey() ([]byte, error) {
+	encoded := os.Getenv("JAYKEY")
+
+	if len(encoded) == 0 {
+		file := os.Getenv("JAYKEYFILE")
+		if len(file) == 0 {
+			return nil, ErrMasterKeyMissing
+		}
+
+		b, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		encoded = string(b)
+	}
+
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil || len(key) != 32 {
+		return nil, ErrMasterKeyInvalid
+	}
+
+	return key, nil
+}
+
+// Seal encrypts the text with AES-256-GCM and returns it with the
+// SealedPrefix so it can be stored in the config file.
+func Seal(key []byte, text string) (string, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+
+	sealed := gcm.Seal(nonce, nonce, []byte(text), nil)
+
+	return SealedPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Open decrypts a value that was encrypted with Seal.
+func Open(key []byte, sealed string) (string, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+
+	b, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(sealed, SealedPrefix))
+	if err != nil || len(b) < gcm.NonceSize() {
+		return "", ErrSealedInvalid
+	}
+
+	text, err := gcm.Open(nil, b[:gcm.NonceSize()], b[gcm.NonceSize():], nil)
+	if err != nil {
+		return "", ErrSealedInvalid
+	}
+
+	return string(text), nil
+}
+
+// newGCM returns the AES-256-GCM cipher for the key.
+func newGCM(key []byte) (cipher.AEAD, error) {
+	if len(key) != 32 {
+		return nil, ErrMasterKeyInvalid
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// Unseal decrypts every sealed string value in the JSON document with the
+// master key and returns the new document. The document is returned unchanged
+// if it does not contain any sealed values.
+func Unseal(data []byte) ([]byte, error) {
+	if !bytes.Contains(data, []byte(`"`+SealedPrefix)) {
+		return data, nil
+	}
+
+	root, err := decode(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var key []byte
+
+	v, err := walkStrings("", root, func(path string, s string) (string, error) {
+		if !strings.HasPrefix(s, SealedPrefix) {
+			return s, nil
+		}
+
+		// Only read the master key once it is needed
+		if key == nil {
+			k, err := MasterKey()
+			if err != nil {
+				return "", err
+			}
+			key = k
+		}
+
+		text, err := Open(key, s)
+		if err != nil {
+			return "", fmt.Errorf("%v: %v", path, err)
+		}
+
+		return text, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(v)
+}
+
+// walkStrings calls fn for every string value in the decoded JSON document and
+// replaces the value with the result.
+func walkStrings(path string, v interface{}, fn func(string, string) (string, error)) (interface{}, error) {
+	var err error
+
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, val := range t {
+			if t[k], err = walkStrings(joinPath(path, k), val, fn); err != nil {
+				return nil, err
+			}
+		}
+	case []interface{}:
+		for i, val := range t {
+			if t[i], err = walkStrings(fmt.Sprintf("%v[%d]", path, i), val, fn); err != nil {
+				return nil, err
+			}
+		}
+	case string:
+		return fn(path, t)
+	}
+
+	return v, nil
+}
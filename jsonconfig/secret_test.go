@@ -0,0 +1,98 @@
+package jsonconfig_test
+
+import (
+	"encoding/base64"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/blue-jay/core/jsonconfig"
+)
+
+// masterKey is a test key.
+var masterKey = []byte("0123456789abcdef0123456789abcdef")
+
+// TestSeal ensures a value can be sealed and opened.
+func TestSeal(t *testing.T) {
+	sealed, err := jsonconfig.Seal(masterKey, "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	text, err := jsonconfig.Open(masterKey, sealed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if text != "secret" {
+		t.Errorf("\n got: %v\nwant: %v", text, "secret")
+	}
+
+	_, err = jsonconfig.Open([]byte("fedcba9876543210fedcba9876543210"), sealed)
+	if err == nil {
+		t.Fatal("Open should have failed with the wrong key.")
+	}
+}
+
+// TestUnseal ensures sealed values are decrypted when loaded.
+func TestUnseal(t *testing.T) {
+	os.Setenv("JAYKEYFILE", "testdata/master.key")
+	defer os.Clearenv()
+
+	i := &Info{}
+	err := jsonconfig.LoadProfile("testdata/envsealed.json", "", i)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "sealedfolder"
+	received := i.Asset.Folder
+
+	if expected != received {
+		t.Errorf("\n got: %v\nwant: %v", received, expected)
+	}
+}
+
+// TestUnsealFail ensures sealed values fail without a valid master key.
+func TestUnsealFail(t *testing.T) {
+	os.Clearenv()
+
+	data := []byte(`{"MySQL":{"Password":"enc:AAAA"}}`)
+
+	_, err := jsonconfig.Unseal(data)
+	if err != jsonconfig.ErrMasterKeyMissing {
+		t.Fatalf("\n got: %v\nwant: %v", err, jsonconfig.ErrMasterKeyMissing)
+	}
+
+	os.Setenv("JAYKEY", base64.StdEncoding.EncodeToString(masterKey))
+	defer os.Clearenv()
+
+	_, err = jsonconfig.Unseal(data)
+	if err == nil {
+		t.Fatal("Unseal should have failed on an invalid value.")
+	}
+
+	os.Setenv("JAYKEY", "short")
+
+	_, err = jsonconfig.MasterKey()
+	if err != jsonconfig.ErrMasterKeyInvalid {
+		t.Fatalf("\n got: %v\nwant: %v", err, jsonconfig.ErrMasterKeyInvalid)
+	}
+}
+
+// TestEffectiveSealed ensures the printed config keeps the values sealed.
+func TestEffectiveSealed(t *testing.T) {
+	os.Setenv("JAYKEYFILE", "testdata/master.key")
+	defer os.Clearenv()
+
+	b, err := jsonconfig.Effective("testdata/envsealed.json", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Contains(string(b), "sealedfolder") {
+		t.Fatalf("Secret should not be printed: %s", b)
+	} else if !strings.Contains(string(b), jsonconfig.SealedPrefix) {
+		t.Fatalf("Sealed value is missing: %s", b)
+	}
+}
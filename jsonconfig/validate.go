@@ -0,0 +1,242 @@
+package jsonconfig
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// FieldError is a problem with a single field.
+type FieldError struct {
+	// Path is the JSON path to the field like MySQL.Migration.Table
+	Path string
+	// Message describes the problem
+	Message string
+}
+
+// Error returns the path and the message.
+func (e FieldError) Error() string {
+	return e.Path + " " + e.Message
+}
+
+// ValidationError holds every problem found during validation.
+type ValidationError []FieldError
+
+// Error returns every problem on a separate line.
+func (e ValidationError) Error() string {
+	list := make([]string, len(e))
+	for i, fe := range e {
+		list[i] = fe.Error()
+	}
+
+	return "Config is invalid:\n" + strings.Join(list, "\n")
+}
+
+// Validate checks every field of v against the rules in the jsonconfig struct
+// tag and returns a ValidationError with every problem found or nil. The tags
+// of other validation packages, like validate, are ignored.
+//
+// The rules are separated by commas:
+//
+//	required      value must not be empty or zero
+//	min=N         number must be at least N, or length must be at least N
+//	max=N         number must be at most N, or length must be at most N
+//	oneof=A B C   value must be one of the space separated values
+//	file          path must exist on disk
+//	base64        value must be base64 encoded
+//	base64=N M    value must be base64 encoded and decode to N or M bytes
+//
+// All rules except required are skipped if the value is empty or zero.
+//
+// Example:
+//
+//	AuthKey string `json:"AuthKey" jsonconfig:"required,base64=64"`
+func Validate(v interface{}) error {
+	var errs ValidationError
+
+	validate("", reflect.ValueOf(v), &errs)
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+// validate walks the value and checks each field that has rules.
+func validate(path string, v reflect.Value, errs *ValidationError) {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if !v.IsNil() {
+			validate(path, v.Elem(), errs)
+		}
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+
+			// Skip unexported fields unless they are embedded
+			if len(f.PkgPath) > 0 && !f.Anonymous {
+				continue
+			}
+
+			key, promoted, ok := fieldKey(f)
+			if !ok {
+				continue
+			}
+
+			child := path
+			if !promoted {
+				child = joinPath(path, key)
+			}
+
+			if rules := f.Tag.Get("jsonconfig"); len(rules) > 0 {
+				for _, msg := range check(v.Field(i), rules) {
+					*errs = append(*errs, FieldError{child, msg})
+				}
+			}
+
+			validate(child, v.Field(i), errs)
+		}
+	case reflect.Map:
+		for _, k := range v.MapKeys() {
+			validate(joinPath(path, fmt.Sprint(k.Interface())), v.MapIndex(k), errs)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			validate(fmt.Sprintf("%v[%d]", path, i), v.Index(i), errs)
+		}
+	}
+}
+
+// joinPath appends the key to the JSON path.
+func joinPath(path, key string) string {
+	if len(path) == 0 {
+		return key
+	}
+
+	return path + "." + key
+}
+
+// check returns a message for each rule the value does not pass.
+func check(v reflect.Value, rules string) []string {
+	var msgs []string
+
+	zero := v.IsZero()
+
+	for _, rule := range strings.Split(rules, ",") {
+		name, arg := rule, ""
+		if i := strings.Index(rule, "="); i >= 0 {
+			name, arg = rule[:i], rule[i+1:]
+		}
+
+		if name == "required" {
+			if zero {
+				return append(msgs, "is required")
+			}
+			continue
+		}
+
+		// Only required applies to empty values
+		if zero {
+			continue
+		}
+
+		var msg string
+
+		switch name {
+		case "min", "max":
+			msg = checkRange(v, name, arg)
+		case "oneof":
+			msg = checkOneOf(v, arg)
+		case "file":
+			if _, err := os.Stat(fmt.Sprint(v.Interface())); err != nil {
+				msg = "must be an existing file: " + fmt.Sprint(v.Interface())
+			}
+		case "base64":
+			msg = checkBase64(v, arg)
+		default:
+			msg = "has an unknown validation rule: " + name
+		}
+
+		if len(msg) > 0 {
+			msgs = append(msgs, msg)
+		}
+	}
+
+	return msgs
+}
+
+// checkRange compares numbers by value and everything else by length.
+func checkRange(v reflect.Value, name, arg string) string {
+	limit, err := strconv.ParseFloat(arg, 64)
+	if err != nil {
+		return fmt.Sprintf("has an invalid %v rule: %v", name, arg)
+	}
+
+	var n float64
+	what := "must be"
+
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n = float64(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n = float64(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		n = v.Float()
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+		n = float64(v.Len())
+		what = "must have a length of"
+	default:
+		return fmt.Sprintf("cannot use the %v rule on type %v", name, v.Type())
+	}
+
+	if name == "min" && n < limit {
+		return fmt.Sprintf("%v at least %v", what, arg)
+	}
+
+	if name == "max" && n > limit {
+		return fmt.Sprintf("%v at most %v", what, arg)
+	}
+
+	return ""
+}
+
+// checkOneOf ensures the value is in the space separated list.
+func checkOneOf(v reflect.Value, arg string) string {
+	s := fmt.Sprint(v.Interface())
+	list := strings.Fields(arg)
+
+	for _, item := range list {
+		if s == item {
+			return ""
+		}
+	}
+
+	return fmt.Sprintf("must be one of: %v", strings.Join(list, ", "))
+}
+
+// checkBase64 ensures the value is base64 encoded and decodes to one of the
+// space separated lengths.
+func checkBase64(v reflect.Value, arg string) string {
+	b, err := base64.StdEncoding.DecodeString(fmt.Sprint(v.Interface()))
+	if err != nil {
+		return "must be base64 encoded"
+	}
+
+	list := strings.Fields(arg)
+	if len(list) == 0 {
+		return ""
+	}
+
+	for _, item := range list {
+		if strconv.Itoa(len(b)) == item {
+			return ""
+		}
+	}
+
+	return fmt.Sprintf("must decode to %v bytes, not %v", strings.Join(list, " or "), len(b))
+}
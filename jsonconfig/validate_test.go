@@ -0,0 +1,114 @@
+package jsonconfig_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/blue-jay/core/jsonconfig"
+)
+
+// ValidateInfo is an example structure with validation rules.
+type ValidateInfo struct {
+	Session     SessionInfo               `json:"Session"`
+	Connections map[string]ConnectionInfo `json:"Connections"`
+	Template    string                    `jsonconfig:"file"`
+}
+
+// SessionInfo holds a test config.
+type SessionInfo struct {
+	Name       string `json:"Name" jsonconfig:"required"`
+	AuthKey    string `json:"AuthKey" jsonconfig:"required,base64=64"`
+	EncryptKey string `json:"EncryptKey" jsonconfig:"base64=16 24 32"`
+	MaxAge     int    `jsonconfig:"min=60,max=86400"`
+}
+
+// ConnectionInfo holds a test config.
+type ConnectionInfo struct {
+	Type string   `jsonconfig:"required,oneof=MySQL PostgreSQL"`
+	Tags []string `jsonconfig:"max=2"`
+}
+
+// TestValidate ensures a valid config passes.
+func TestValidate(t *testing.T) {
+	i := &ValidateInfo{
+		Session: SessionInfo{
+			Name:    "sess",
+			AuthKey: "PzCh6FNAB7/jhmlUQ0+25sjJ+WgcJeKR2bAOtnh9UnfVN+WJSBvY/YC80Rs+rbMtwfmSP4FUSxKPtpYKzKFqFA==",
+			MaxAge:  28800,
+		},
+		Connections: map[string]ConnectionInfo{
+			"legacy": {Type: "MySQL"},
+		},
+		Template: "testdata/envtest.json",
+	}
+
+	err := jsonconfig.Validate(i)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestValidateFail ensures every problem is returned with the path.
+func TestValidateFail(t *testing.T) {
+	i := &ValidateInfo{
+		Session: SessionInfo{
+			AuthKey:    "bad auth key",
+			EncryptKey: "xULAGF5FcWvqHsXaovNFJYfgCt6pedRPROqNvsZjU18AAAAA",
+			MaxAge:     30,
+		},
+		Connections: map[string]ConnectionInfo{
+			"legacy": {Type: "SQLite", Tags: []string{"a", "b", "c"}},
+		},
+		Template: "testdata/missing.tmpl",
+	}
+
+	err := jsonconfig.Validate(i)
+	if err == nil {
+		t.Fatal("Validate should have failed.")
+	}
+
+	errs, ok := err.(jsonconfig.ValidationError)
+	if !ok {
+		t.Fatalf("Error should be a ValidationError: %T", err)
+	}
+
+	expected := []string{
+		"Session.Name is required",
+		"Session.AuthKey must be base64 encoded",
+		"Session.EncryptKey must decode to 16 or 24 or 32 bytes, not 36",
+		"Session.MaxAge must be at least 60",
+		"Connections.legacy.Type must be one of: MySQL, PostgreSQL",
+		"Connections.legacy.Tags must have a length of at most 2",
+		"Template must be an existing file: testdata/missing.tmpl",
+	}
+
+	if len(errs) != len(expected) {
+		t.Fatalf("\n got: %v\nwant: %v", err, strings.Join(expected, "\n"))
+	}
+
+	for n, e := range errs {
+		if e.Error() != expected[n] {
+			t.Errorf("\n got: %v\nwant: %v", e.Error(), expected[n])
+		}
+	}
+}
+
+// ForeignInfo has the tags of another validation package.
+type ForeignInfo struct {
+	Email string `json:"Email" validate:"required,email"`
+	Name  string `json:"Name" validate:"required" jsonconfig:"required"`
+}
+
+// TestValidateForeign ensures the tags of other validation packages are
+// ignored.
+func TestValidateForeign(t *testing.T) {
+	i := &ForeignInfo{Name: "bob"}
+
+	if err := jsonconfig.Validate(i); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := jsonconfig.Validate(&ForeignInfo{}); err == nil || err.Error() != "Config is invalid:\nName is required" {
+		t.Fatalf("Only the jsonconfig rules should be checked, got: %v", err)
+	}
+}
@@ -0,0 +1,169 @@
+package jsonconfig
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SubscribeFunc is called with the old and new config after a reload.
+type SubscribeFunc func(old Parser, new Parser)
+
+// Watcher reloads the config file when it or one of its profile files
+// changes. The new config is only used if it loads and validates, otherwise
+// the old config is kept.
+type Watcher struct {
+	configFile string
+	profile    string
+	newParser  func() Parser
+
+	current     Parser
+	modified    string
+	subscribers []SubscribeFunc
+	errorFunc   func(error)
+	mutex       sync.RWMutex
+	reloadMutex sync.Mutex
+
+	stop chan struct{}
+	once sync.Once
+}
+
+// Watch loads the config file with the profile from the JAYPROFILE environment
+// variable and then checks the files for changes at every interval. The
+// newParser func must return a new empty struct each time it is called. An
+// error is returned if the interval is not greater than zero.
+func Watch(configFile string, interval time.Duration, newParser func() Parser) (*Watcher, error) {
+	if interval <= 0 {
+		return nil, errors.New("Watch interval must be greater than zero.")
+	}
+
+	w := &Watcher{
+		configFile: configFile,
+		profile:    os.Getenv("JAYPROFILE"),
+		newParser:  newParser,
+		errorFunc: func(err error) {
+			log.Println("Config reload error:", err)
+		},
+		stop: make(chan struct{}),
+	}
+
+	// Load the config for the first time
+	p := newParser()
+	if err := LoadProfile(configFile, w.profile, p); err != nil {
+		return nil, err
+	}
+	w.current = p
+	w.modified = w.fingerprint()
+
+	go w.poll(interval)
+
+	return w, nil
+}
+
+// Current returns the config that was loaded last.
+func (w *Watcher) Current() Parser {
+	w.mutex.RLock()
+	defer w.mutex.RUnlock()
+	return w.current
+}
+
+// Subscribe adds a func that is called after each successful reload.
+func (w *Watcher) Subscribe(fn SubscribeFunc) {
+	w.mutex.Lock()
+	w.subscribers = append(w.subscribers, fn)
+	w.mutex.Unlock()
+}
+
+// OnError sets the func that is called when a reload fails after a change.
+// The default func logs the error.
+func (w *Watcher) OnError(fn func(error)) {
+	w.mutex.Lock()
+	w.errorFunc = fn
+	w.mutex.Unlock()
+}
+
+// Reload loads the config file and notifies the subscribers. If the file
+// cannot be loaded or is invalid, the error is returned and the old config is
+// kept. Reloads run one at a time so an older config never replaces a newer
+// one, so a subscriber must not call Reload.
+func (w *Watcher) Reload() error {
+	w.reloadMutex.Lock()
+	defer w.reloadMutex.Unlock()
+
+	p := w.newParser()
+	if err := LoadProfile(w.configFile, w.profile, p); err != nil {
+		return err
+	}
+
+	w.mutex.Lock()
+	old := w.current
+	w.current = p
+	list := w.subscribers
+	w.mutex.Unlock()
+
+	for _, fn := range list {
+		fn(old, p)
+	}
+
+	return nil
+}
+
+// Stop ends the checks for changes.
+func (w *Watcher) Stop() {
+	w.once.Do(func() {
+		close(w.stop)
+	})
+}
+
+// poll reloads the config when the files change.
+func (w *Watcher) poll(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.stop:
+			return
+		case <-ticker.C:
+			modified := w.fingerprint()
+			if modified == w.modified {
+				continue
+			}
+			w.modified = modified
+
+			if err := w.Reload(); err != nil {
+				w.mutex.RLock()
+				fn := w.errorFunc
+				w.mutex.RUnlock()
+
+				if fn != nil {
+					fn(err)
+				}
+			}
+		}
+	}
+}
+
+// fingerprint returns the modification time and size of the config file and
+// the profile files.
+func (w *Watcher) fingerprint() string {
+	files := []string{w.configFile}
+	for _, name := range strings.Split(w.profile, ",") {
+		if name = strings.TrimSpace(name); len(name) > 0 {
+			files = append(files, ProfileFile(w.configFile, name))
+		}
+	}
+
+	s := ""
+	for _, file := range files {
+		if fi, err := os.Stat(file); err == nil {
+			s += fmt.Sprintf("%v:%v;", fi.ModTime().UnixNano(), fi.Size())
+		}
+	}
+
+	return s
+}
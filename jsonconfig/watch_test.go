@@ -0,0 +1,145 @@
+package jsonconfig_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/blue-jay/core/jsonconfig"
+)
+
+// newInfo returns a new config for the watcher.
+func newInfo() jsonconfig.Parser {
+	return &Info{}
+}
+
+// TestWatch ensures subscribers are notified when the file changes.
+func TestWatch(t *testing.T) {
+	dir, err := ioutil.TempDir("", "jsonconfig")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "env.json")
+	ioutil.WriteFile(file, []byte(`{"Asset":{"Folder":"foofolder"}}`), 0644)
+
+	w, err := jsonconfig.Watch(file, 10*time.Millisecond, newInfo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Stop()
+
+	changed := make(chan string, 1)
+	w.Subscribe(func(old, new jsonconfig.Parser) {
+		changed <- old.(*Info).Asset.Folder + ":" + new.(*Info).Asset.Folder
+	})
+
+	ioutil.WriteFile(file, []byte(`{"Asset":{"Folder":"barfolder2"}}`), 0644)
+
+	select {
+	case received := <-changed:
+		expected := "foofolder:barfolder2"
+		if expected != received {
+			t.Errorf("\n got: %v\nwant: %v", received, expected)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Subscriber was not notified.")
+	}
+
+	if w.Current().(*Info).Asset.Folder != "barfolder2" {
+		t.Errorf("\n got: %v\nwant: %v", w.Current().(*Info).Asset.Folder, "barfolder2")
+	}
+}
+
+// TestWatchInvalid ensures the old config is kept when the file is invalid.
+func TestWatchInvalid(t *testing.T) {
+	dir, err := ioutil.TempDir("", "jsonconfig")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "env.json")
+	ioutil.WriteFile(file, []byte(`{"Asset":{"Folder":"foofolder"}}`), 0644)
+
+	w, err := jsonconfig.Watch(file, time.Hour, newInfo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Stop()
+
+	notified := false
+	w.Subscribe(func(old, new jsonconfig.Parser) {
+		notified = true
+	})
+
+	ioutil.WriteFile(file, []byte(`{"Asset":{"Folder":"barfolder",},}`), 0644)
+
+	err = w.Reload()
+	if err == nil {
+		t.Fatal("Reload should have failed on malformed json.")
+	}
+
+	if notified {
+		t.Error("Subscriber should not be notified.")
+	}
+
+	if w.Current().(*Info).Asset.Folder != "foofolder" {
+		t.Errorf("\n got: %v\nwant: %v", w.Current().(*Info).Asset.Folder, "foofolder")
+	}
+}
+
+// TestWatchFail ensures a missing file fails.
+func TestWatchFail(t *testing.T) {
+	_, err := jsonconfig.Watch("testdata/envtest-missing.json", time.Hour, newInfo)
+	if err == nil {
+		t.Fatal("File should have failed because it is missing.")
+	}
+}
+
+// TestWatchInterval ensures an interval that is not positive is an error.
+func TestWatchInterval(t *testing.T) {
+	for _, interval := range []time.Duration{0, -time.Second} {
+		if _, err := jsonconfig.Watch("testdata/envtest.json", interval, newInfo); err == nil {
+			t.Fatalf("Interval %v should have failed.", interval)
+		}
+	}
+}
+
+// TestReloadConcurrent ensures concurrent reloads notify the subscribers one
+// at a time.
+func TestReloadConcurrent(t *testing.T) {
+	w, err := jsonconfig.Watch("testdata/envtest.json", time.Hour, newInfo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Stop()
+
+	var last jsonconfig.Parser
+	w.Subscribe(func(old, new jsonconfig.Parser) {
+		if old != last && last != nil {
+			t.Error("Subscriber should get the config from the previous reload.")
+		}
+		last = new
+	})
+
+	done := make(chan bool)
+	for i := 0; i < 10; i++ {
+		go func() {
+			if err := w.Reload(); err != nil {
+				t.Error(err)
+			}
+			done <- true
+		}()
+	}
+	for i := 0; i < 10; i++ {
+		<-done
+	}
+
+	if w.Current() != last {
+		t.Fatal("Current config should be the last one loaded.")
+	}
+}
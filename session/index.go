@@ -0,0 +1,169 @@
+package session
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net"
+	"net/http"
+	"sort"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gorilla/sessions"
+)
+
+// userKey is the session value with the user ID for the index.
+const userKey = "_user"
+
+var (
+	// ErrIndexUnsupported is when the store does not keep an index of the
+	// sessions so they cannot be listed or revoked: cookie and filesystem
+	ErrIndexUnsupported = errors.New("Session store does not support an index of users.")
+)
+
+// Active is a session for a user that has not expired.
+type Active struct {
+	ID        string    // Identifies the session for Revoke, it is not the session ID
+	Created   time.Time // When the session was first saved
+	LastSeen  time.Time // When the session was last loaded or saved
+	IP        string    // Remote address of the last request that saved the session
+	UserAgent string    // User agent of the last request that saved the session
+}
+
+// meta is the information about the request that saved the session.
+type meta struct {
+	user  string
+	ip    string
+	agent string
+}
+
+// newMeta returns the information about the request and the user of the
+// session.
+func newMeta(r *http.Request, sess *sessions.Session) meta {
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		ip = r.RemoteAddr
+	}
+
+	// Cut the user agent on a rune boundary so it stays valid UTF-8
+	agent := r.UserAgent()
+	if len(agent) > 255 {
+		n := 255
+		for n > 0 && !utf8.RuneStart(agent[n]) {
+			n--
+		}
+		agent = agent[:n]
+	}
+
+	return meta{
+		user:  User(sess),
+		ip:    ip,
+		agent: agent,
+	}
+}
+
+// SetUser adds the session to the index for the user when it is saved. Only
+// the memory, mysql, and postgresql stores keep an index.
+func SetUser(sess *sessions.Session, userID string) {
+	sess.Values[userKey] = userID
+}
+
+// User returns the user ID from SetUser or an empty string.
+func User(sess *sessions.Session) string {
+	user, _ := sess.Values[userKey].(string)
+	return user
+}
+
+// Handle returns the ID of the session in the list from Sessions so the
+// current session can be marked or skipped.
+func Handle(sess *sessions.Session) string {
+	if len(sess.ID) == 0 {
+		return ""
+	}
+
+	return handle(sess.ID)
+}
+
+// handle hashes the session ID so it is safe to show.
+func handle(id string) string {
+	sum := sha256.Sum256([]byte(id))
+	return hex.EncodeToString(sum[:16])
+}
+
+// backend returns the backend for the store or an error if the store does not
+// keep an index.
+func (i *Info) backend() (backend, error) {
+	if s, ok := i.store.(*serverStore); ok {
+		return s.backend, nil
+	}
+
+	return nil, ErrIndexUnsupported
+}
+
+// Sessions returns the active sessions for the user with the most recently
+// seen first.
+func (i *Info) Sessions(userID string) ([]Active, error) {
+	b, err := i.backend()
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := b.list(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	for n := range list {
+		list[n].ID = handle(list[n].ID)
+	}
+
+	sort.Slice(list, func(a, b int) bool {
+		return list[a].LastSeen.After(list[b].LastSeen)
+	})
+
+	return list, nil
+}
+
+// Revoke removes the session with the ID from Sessions for the user. It
+// returns ErrSessionNotFound if the user has no active session with the ID.
+func (i *Info) Revoke(userID string, id string) error {
+	b, err := i.backend()
+	if err != nil {
+		return err
+	}
+
+	list, err := b.list(userID)
+	if err != nil {
+		return err
+	}
+
+	for _, a := range list {
+		if handle(a.ID) == id {
+			return b.erase(a.ID)
+		}
+	}
+
+	return ErrSessionNotFound
+}
+
+// RevokeAll removes all the sessions for the user.
+func (i *Info) RevokeAll(userID string) error {
+	b, err := i.backend()
+	if err != nil {
+		return err
+	}
+
+	list, err := b.list(userID)
+	if err != nil {
+		return err
+	}
+
+	for _, a := range list {
+		if err := b.erase(a.ID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,150 @@
+package session_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/blue-jay/core/session"
+)
+
+// login saves a session for the user from a device and returns the cookie.
+func login(t *testing.T, s *session.Info, user string, agent string) *http.Cookie {
+	w := httptest.NewRecorder()
+	r, err := http.NewRequest("GET", "/", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.RemoteAddr = "192.0.2.1:1234"
+	r.Header.Set("User-Agent", agent)
+
+	sess, err := s.Instance(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	session.SetUser(sess, user)
+	if err := sess.Save(r, w); err != nil {
+		t.Fatal(err)
+	}
+
+	return w.Result().Cookies()[0]
+}
+
+// TestSessions ensures the sessions for a user are listed and revoked.
+func TestSessions(t *testing.T) {
+	s := newInfo(session.StoreMemory)
+	if err := s.SetupConfig(); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	phone := login(t, &s, "1", "phone")
+	laptop := login(t, &s, "1", "laptop")
+	other := login(t, &s, "2", "tablet")
+
+	list, err := s.Sessions("1")
+	if err != nil {
+		t.Fatal(err)
+	} else if len(list) != 2 {
+		t.Fatalf("Expected 2 sessions, got: %v", len(list))
+	}
+
+	if list[0].IP != "192.0.2.1" || list[0].Created.IsZero() || list[0].LastSeen.IsZero() {
+		t.Fatalf("Session information is missing: %+v", list[0])
+	}
+
+	// Revoke the phone
+	sess, _ := s.Instance(next(t, phone))
+	if err := s.Revoke("1", session.Handle(sess)); err != nil {
+		t.Fatal(err)
+	}
+
+	if sess, _ := s.Instance(next(t, phone)); !sess.IsNew {
+		t.Fatal("Revoked session should be removed.")
+	} else if sess, _ := s.Instance(next(t, laptop)); sess.IsNew {
+		t.Fatal("Other session should not be removed.")
+	}
+
+	// Another user cannot revoke the session
+	sess, _ = s.Instance(next(t, laptop))
+	if err := s.Revoke("2", session.Handle(sess)); err != session.ErrSessionNotFound {
+		t.Fatalf("Expected %v, got: %v", session.ErrSessionNotFound, err)
+	}
+
+	// Log out of all devices
+	if err := s.RevokeAll("1"); err != nil {
+		t.Fatal(err)
+	}
+
+	if list, _ := s.Sessions("1"); len(list) != 0 {
+		t.Fatalf("Expected 0 sessions, got: %v", len(list))
+	} else if sess, _ := s.Instance(next(t, other)); sess.IsNew {
+		t.Fatal("Session for another user should not be removed.")
+	}
+}
+
+// TestSessionsLastSeen ensures a request that only loads the session updates
+// when it was last seen.
+func TestSessionsLastSeen(t *testing.T) {
+	s := newInfo(session.StoreMemory)
+	if err := s.SetupConfig(); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	phone := login(t, &s, "1", "phone")
+	time.Sleep(time.Millisecond)
+	login(t, &s, "1", "laptop")
+	time.Sleep(time.Millisecond)
+
+	if _, err := s.Instance(next(t, phone)); err != nil {
+		t.Fatal(err)
+	}
+
+	list, err := s.Sessions("1")
+	if err != nil {
+		t.Fatal(err)
+	} else if len(list) != 2 || list[0].UserAgent != "phone" {
+		t.Fatalf("Phone should be the most recently seen: %+v", list)
+	}
+}
+
+// TestSessionsUserAgent ensures a long user agent is cut without splitting a
+// rune.
+func TestSessionsUserAgent(t *testing.T) {
+	s := newInfo(session.StoreMemory)
+	if err := s.SetupConfig(); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	login(t, &s, "1", strings.Repeat("é", 200))
+
+	list, err := s.Sessions("1")
+	if err != nil {
+		t.Fatal(err)
+	} else if len(list) != 1 {
+		t.Fatalf("Expected 1 session, got: %v", len(list))
+	}
+
+	agent := list[0].UserAgent
+	if len(agent) != 254 || !utf8.ValidString(agent) {
+		t.Fatalf("User agent is not cut on a rune boundary: %v bytes", len(agent))
+	}
+}
+
+// TestSessionsUnsupported ensures the cookie store does not keep an index.
+func TestSessionsUnsupported(t *testing.T) {
+	s := newInfo(session.StoreCookie)
+	if err := s.SetupConfig(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.Sessions("1"); err != session.ErrIndexUnsupported {
+		t.Fatalf("Expected %v, got: %v", session.ErrIndexUnsupported, err)
+	}
+}
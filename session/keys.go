@@ -0,0 +1,62 @@
+package session
+
+import (
+	"encoding/base64"
+	"errors"
+	"net/http"
+)
+
+// KeyPair holds an old pair of keys that is still accepted after a rotation.
+type KeyPair struct {
+	AuthKey    string `json:"AuthKey" jsonconfig:"required,base64=32 64"`
+	EncryptKey string `json:"EncryptKey" jsonconfig:"base64=16 24 32"`
+}
+
+// keyPairs returns the decoded AuthKey and EncryptKey followed by the decoded
+// OldKeys. A missing EncryptKey is returned as nil so the keys are always in
+// pairs.
+func (i *Info) keyPairs() ([][]byte, error) {
+	pairs := append([]KeyPair{{i.AuthKey, i.EncryptKey}}, i.OldKeys...)
+
+	keys := make([][]byte, 0, len(pairs)*2)
+
+	for _, p := range pairs {
+		// Decode authentication key
+		auth, err := base64.StdEncoding.DecodeString(p.AuthKey)
+		if err != nil {
+			return nil, err
+		} else if len(auth) == 0 {
+			return nil, errors.New("Session AuthKey is missing in OldKeys.")
+		}
+
+		var encrypt []byte
+
+		// Decode the encrypt key
+		if len(p.EncryptKey) > 0 {
+			encrypt, err = base64.StdEncoding.DecodeString(p.EncryptKey)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		keys = append(keys, auth, encrypt)
+	}
+
+	return keys, nil
+}
+
+// stale returns true if the session cookie was not signed with the current
+// keys.
+func (i *Info) stale(r *http.Request) bool {
+	if len(i.OldKeys) == 0 || i.current == nil {
+		return false
+	}
+
+	c, err := r.Cookie(i.Name)
+	if err != nil {
+		return false
+	}
+
+	var b []byte
+	return i.current.Decode(i.Name, c.Value, &b) != nil
+}
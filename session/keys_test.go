@@ -0,0 +1,172 @@
+package session_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/blue-jay/core/session"
+)
+
+// save stores a value in a new session and returns the cookie.
+func save(t *testing.T, s *session.Info, text string) *http.Cookie {
+	w := httptest.NewRecorder()
+	r, err := http.NewRequest("GET", "/", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sess, err := s.Instance(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sess.Values["test"] = text
+	if err := sess.Save(r, w); err != nil {
+		t.Fatal(err)
+	}
+
+	cookies := w.Result().Cookies()
+	if len(cookies) != 1 {
+		t.Fatalf("Expected 1 cookie, got: %v", len(cookies))
+	}
+
+	return cookies[0]
+}
+
+// load returns the value from the session with the cookie.
+func load(s *session.Info, c *http.Cookie) (interface{}, error) {
+	r, err := http.NewRequest("GET", "/", nil)
+	if err != nil {
+		return nil, err
+	}
+	r.AddCookie(c)
+
+	sess, err := s.Instance(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return sess.Values["test"], nil
+}
+
+// rotated returns a config with new keys and the keys from old in OldKeys.
+func rotated(old session.Info) session.Info {
+	s := old
+	s.AuthKey = "1OUl1lRaVgV09IpercnMUCGldmNNcfxSDK8hAuXysb20FnDVcwy1iFXDRJQvnDZim7vs4YoFKyOX0iC49QdRWA=="
+	s.EncryptKey = "bmV3LWVuY3J5cHQta2V5LW5ldy1lbmNyeXB0LWtleSE="
+	s.OldKeys = []session.KeyPair{{AuthKey: old.AuthKey, EncryptKey: old.EncryptKey}}
+	return s
+}
+
+// TestOldKeys ensures a cookie from the old keys is accepted after a rotation.
+func TestOldKeys(t *testing.T) {
+	old := newInfo(session.StoreCookie)
+	if err := old.SetupConfig(); err != nil {
+		t.Fatal(err)
+	}
+
+	c := save(t, &old, "foo123")
+
+	s := rotated(old)
+	if err := s.SetupConfig(); err != nil {
+		t.Fatal(err)
+	}
+
+	val, err := load(&s, c)
+	if err != nil {
+		t.Fatal(err)
+	} else if val != "foo123" {
+		t.Fatalf(`Text should be: "%v", but is wrong: "%v"`, "foo123", val)
+	}
+
+	// Without the old keys, the cookie is rejected
+	s.OldKeys = nil
+	if err := s.SetupConfig(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := load(&s, c); err == nil {
+		t.Fatal("Expected an error for a cookie signed with the old keys.")
+	}
+}
+
+// TestReissue ensures a cookie from the old keys is signed with the new keys.
+func TestReissue(t *testing.T) {
+	old := newInfo(session.StoreCookie)
+	if err := old.SetupConfig(); err != nil {
+		t.Fatal(err)
+	}
+
+	c := save(t, &old, "foo123")
+
+	s := rotated(old)
+	if err := s.SetupConfig(); err != nil {
+		t.Fatal(err)
+	}
+
+	w := httptest.NewRecorder()
+	r, err := http.NewRequest("GET", "/", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.AddCookie(c)
+
+	if err := s.Reissue(w, r); err != nil {
+		t.Fatal(err)
+	}
+
+	cookies := w.Result().Cookies()
+	if len(cookies) != 1 {
+		t.Fatalf("Expected 1 cookie, got: %v", len(cookies))
+	}
+
+	// The new cookie works with only the new keys
+	s.OldKeys = nil
+	if err := s.SetupConfig(); err != nil {
+		t.Fatal(err)
+	}
+
+	val, err := load(&s, cookies[0])
+	if err != nil {
+		t.Fatal(err)
+	} else if val != "foo123" {
+		t.Fatalf(`Text should be: "%v", but is wrong: "%v"`, "foo123", val)
+	}
+}
+
+// TestReissueCurrent ensures a cookie with the current keys is not reissued.
+func TestReissueCurrent(t *testing.T) {
+	s := rotated(newInfo(session.StoreMemory))
+	if err := s.SetupConfig(); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	c := save(t, &s, "foo123")
+
+	w := httptest.NewRecorder()
+	r, err := http.NewRequest("GET", "/", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.AddCookie(c)
+
+	if err := s.Reissue(w, r); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(w.Result().Cookies()) != 0 {
+		t.Fatal("Cookie should not be reissued.")
+	}
+}
+
+// TestOldKeysBad ensures an invalid old key is an error.
+func TestOldKeysBad(t *testing.T) {
+	s := newInfo(session.StoreCookie)
+	s.OldKeys = []session.KeyPair{{AuthKey: "%%%"}}
+
+	if err := s.SetupConfig(); err == nil {
+		t.Fatal("Expected an error for an invalid old key.")
+	}
+}
@@ -0,0 +1,149 @@
+package session
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gorilla/sessions"
+)
+
+const (
+	// ExpiredIdle is the reason when the session was not used for IdleTimeout
+	ExpiredIdle = "idle"
+	// ExpiredAbsolute is the reason when the session is older than
+	// AbsoluteTimeout
+	ExpiredAbsolute = "absolute"
+
+	// createdKey is the session value with when the session started
+	createdKey = "_created"
+	// accessedKey is the session value with when the session was last saved
+	accessedKey = "_accessed"
+)
+
+// Expired returns the reason the session expired during the request or an
+// empty string if it did not: ExpiredIdle or ExpiredAbsolute. It requires the
+// Middleware, use InstanceExpired without it.
+func Expired(r *http.Request) string {
+	if s := current(r); s != nil {
+		return s.expired
+	}
+
+	return ""
+}
+
+// InstanceExpired returns an instance of the store like Instance and the
+// reason the session expired or an empty string if it did not. The session is
+// cached for the request so only the first call returns the reason. Save the
+// session after it expires or the browser keeps sending the expired cookie;
+// the Middleware does this.
+func (i *Info) InstanceExpired(r *http.Request) (*sessions.Session, string, error) {
+	sess, err := i.store.Get(r, i.Name)
+	if err != nil {
+		return sess, "", err
+	}
+
+	reason, err := i.expire(r, sess)
+
+	return sess, reason, err
+}
+
+// Regenerate issues a new session ID and saves the session. Only the values
+// for the keys in keep are copied to the new session. Call it after a login to
+// prevent session fixation.
+func (i *Info) Regenerate(w http.ResponseWriter, r *http.Request, keep ...string) (*sessions.Session, error) {
+	sess, err := i.Instance(r)
+	if sess == nil {
+		return nil, err
+	}
+
+	values := make(map[string]interface{})
+	for _, k := range keep {
+		if v, ok := sess.Values[k]; ok {
+			values[k] = v
+		}
+	}
+
+	// Remove the old session from the store
+	if err := i.erase(r, sess); err != nil {
+		return sess, err
+	}
+
+	for k, v := range values {
+		sess.Values[k] = v
+	}
+
+	if i.IdleTimeout > 0 || i.AbsoluteTimeout > 0 {
+		now := time.Now().Unix()
+		sess.Values[createdKey] = now
+		sess.Values[accessedKey] = now
+	}
+
+	return sess, sess.Save(r, w)
+}
+
+// expire empties the session if it is past the IdleTimeout or
+// AbsoluteTimeout and returns the reason. It also records the time of the
+// request which is only stored when the session is saved.
+func (i *Info) expire(r *http.Request, sess *sessions.Session) (string, error) {
+	if i.IdleTimeout <= 0 && i.AbsoluteTimeout <= 0 {
+		return "", nil
+	}
+
+	now := time.Now().Unix()
+	created, _ := sess.Values[createdKey].(int64)
+	accessed, _ := sess.Values[accessedKey].(int64)
+
+	reason := ""
+
+	switch {
+	case created == 0:
+		// The session is new
+	case i.AbsoluteTimeout > 0 && now-created >= int64(i.AbsoluteTimeout):
+		reason = ExpiredAbsolute
+	case i.IdleTimeout > 0 && now-accessed >= int64(i.IdleTimeout):
+		reason = ExpiredIdle
+	}
+
+	if len(reason) > 0 {
+		if err := i.erase(r, sess); err != nil {
+			return reason, err
+		}
+		created = 0
+	}
+
+	if created == 0 {
+		sess.Values[createdKey] = now
+	}
+	sess.Values[accessedKey] = now
+
+	return reason, nil
+}
+
+// erase removes the session from the store and empties the values so the
+// next save uses a new session ID.
+func (i *Info) erase(r *http.Request, sess *sessions.Session) error {
+	if !sess.IsNew && len(sess.ID) > 0 {
+		opts := *sess.Options
+		sess.Options.MaxAge = -1
+
+		// The cookie is replaced on the next save so it is not written
+		err := sess.Save(r, discard{})
+		*sess.Options = opts
+		if err != nil {
+			return err
+		}
+	}
+
+	Empty(sess)
+	sess.ID = ""
+	sess.IsNew = true
+
+	return nil
+}
+
+// discard is a http.ResponseWriter that ignores everything.
+type discard struct{}
+
+func (discard) Header() http.Header         { return http.Header{} }
+func (discard) Write(b []byte) (int, error) { return len(b), nil }
+func (discard) WriteHeader(int)             {}
@@ -0,0 +1,223 @@
+package session_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/blue-jay/core/session"
+)
+
+// next returns a request with the cookie.
+func next(t *testing.T, c *http.Cookie) *http.Request {
+	r, err := http.NewRequest("GET", "/", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.AddCookie(c)
+	return r
+}
+
+// TestRegenerate ensures a new session ID is issued with only the kept values.
+func TestRegenerate(t *testing.T) {
+	s := newInfo(session.StoreMemory)
+	if err := s.SetupConfig(); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	c := save(t, &s, "foo123")
+
+	r := next(t, c)
+	sess, _ := s.Instance(r)
+	sess.Values["user"] = "bob"
+	oldID := sess.ID
+
+	w := httptest.NewRecorder()
+	sess, err := s.Regenerate(w, r, "user")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if sess.ID == oldID || len(sess.ID) == 0 {
+		t.Fatalf("Session ID should change: %v", sess.ID)
+	}
+
+	cookies := w.Result().Cookies()
+	if len(cookies) != 1 {
+		t.Fatalf("Expected 1 cookie, got: %v", len(cookies))
+	}
+
+	sess2, err := s.Instance(next(t, cookies[0]))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if sess2.Values["user"] != "bob" {
+		t.Fatalf("Kept value is missing: %v", sess2.Values)
+	} else if _, ok := sess2.Values["test"]; ok {
+		t.Fatal("Value should not be kept.")
+	}
+
+	// The old session ID no longer works
+	sess3, err := s.Instance(next(t, c))
+	if err != nil {
+		t.Fatal(err)
+	} else if !sess3.IsNew {
+		t.Fatal("Old session should be removed.")
+	}
+}
+
+// TestIdleTimeout ensures an unused session expires.
+func TestIdleTimeout(t *testing.T) {
+	s := newInfo(session.StoreCookie)
+	s.IdleTimeout = 1
+	if err := s.SetupConfig(); err != nil {
+		t.Fatal(err)
+	}
+
+	c := save(t, &s, "foo123")
+
+	// Still valid
+	sess, reason, _ := s.InstanceExpired(next(t, c))
+	if sess.Values["test"] != "foo123" || len(reason) > 0 {
+		t.Fatal("Session should not expire yet.")
+	}
+
+	time.Sleep(1100 * time.Millisecond)
+
+	r := next(t, c)
+	sess, reason, err := s.InstanceExpired(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := sess.Values["test"]; ok {
+		t.Fatal("Session should be empty after it expires.")
+	} else if reason != session.ExpiredIdle {
+		t.Fatalf("Reason should be %v, got: %v", session.ExpiredIdle, reason)
+	}
+
+	// The request is not changed
+	if len(session.Expired(r)) > 0 {
+		t.Fatal("Reason should only be in the request with the middleware.")
+	}
+}
+
+// TestExpiredMiddleware ensures the reason is kept for the rest of the
+// request with the middleware.
+func TestExpiredMiddleware(t *testing.T) {
+	s := newInfo(session.StoreCookie)
+	s.IdleTimeout = 1
+	if err := s.SetupConfig(); err != nil {
+		t.Fatal(err)
+	}
+
+	c := save(t, &s, "foo123")
+
+	time.Sleep(1100 * time.Millisecond)
+
+	serve(&s, func(w http.ResponseWriter, r *http.Request) {
+		if reason := session.Expired(r); reason != session.ExpiredIdle {
+			t.Fatalf("Reason should be %v, got: %v", session.ExpiredIdle, reason)
+		}
+
+		// Instance returns the cached session
+		if sess, err := s.Instance(r); err != nil {
+			t.Fatal(err)
+		} else if _, ok := sess.Values["test"]; ok {
+			t.Fatal("Session should be empty after it expires.")
+		} else if session.Expired(r) != session.ExpiredIdle {
+			t.Fatal("Reason should be kept for the request.")
+		}
+	}, c)
+}
+
+// TestIdleTimeoutMiddleware ensures requests that only read the session keep
+// it from the IdleTimeout.
+func TestIdleTimeoutMiddleware(t *testing.T) {
+	s := newInfo(session.StoreMemory)
+	s.IdleTimeout = 3
+	if err := s.SetupConfig(); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	c := save(t, &s, "foo123")
+
+	for n := 0; n < 3; n++ {
+		time.Sleep(1100 * time.Millisecond)
+
+		res := serve(&s, func(w http.ResponseWriter, r *http.Request) {
+			if reason := session.Expired(r); len(reason) > 0 {
+				t.Fatalf("Session should not expire, got: %v", reason)
+			} else if session.GetString(r, "test") != "foo123" {
+				t.Fatal("Value is missing.")
+			}
+		}, c)
+
+		if cookies := res.Cookies(); len(cookies) != 1 {
+			t.Fatal("Session should be saved on every request.")
+		} else {
+			c = cookies[0]
+		}
+	}
+}
+
+// TestExpiredReplaced ensures the cookie of an expired session is replaced so
+// the reason is only returned once.
+func TestExpiredReplaced(t *testing.T) {
+	s := newInfo(session.StoreCookie)
+	s.IdleTimeout = 1
+	if err := s.SetupConfig(); err != nil {
+		t.Fatal(err)
+	}
+
+	c := save(t, &s, "foo123")
+
+	time.Sleep(1100 * time.Millisecond)
+
+	for n, expected := range []string{session.ExpiredIdle, "", ""} {
+		res := serve(&s, func(w http.ResponseWriter, r *http.Request) {
+			if reason := session.Expired(r); reason != expected {
+				t.Fatalf("Request %v: reason should be %q, got: %q", n, expected, reason)
+			}
+		}, c)
+
+		cookies := res.Cookies()
+		if len(cookies) == 1 {
+			c = cookies[0]
+		} else if len(expected) > 0 {
+			t.Fatal("Cookie of the expired session should be replaced.")
+		}
+	}
+}
+
+// TestAbsoluteTimeout ensures a session expires even when it is used.
+func TestAbsoluteTimeout(t *testing.T) {
+	s := newInfo(session.StoreMemory)
+	s.IdleTimeout = 60
+	s.AbsoluteTimeout = 1
+	if err := s.SetupConfig(); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	c := save(t, &s, "foo123")
+
+	time.Sleep(1100 * time.Millisecond)
+
+	sess, reason, err := s.InstanceExpired(next(t, c))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := sess.Values["test"]; ok {
+		t.Fatal("Session should be empty after it expires.")
+	} else if reason != session.ExpiredAbsolute {
+		t.Fatalf("Reason should be %v, got: %v", session.ExpiredAbsolute, reason)
+	} else if !sess.IsNew || len(sess.ID) > 0 {
+		t.Fatal("Session should get a new ID.")
+	}
+}
@@ -0,0 +1,194 @@
+package session
+
+import (
+	"bufio"
+	"context"
+	"log"
+	"net"
+	"net/http"
+	"reflect"
+
+	"github.com/gorilla/sessions"
+)
+
+// contextKey is the type for values stored in the request context.
+type contextKey int
+
+// stateKey is the request context key for the session from the middleware.
+const stateKey contextKey = 0
+
+// state is the session for a request from the middleware.
+type state struct {
+	sess     *sessions.Session
+	values   map[interface{}]interface{}
+	expired  string
+	modified bool
+	saved    bool
+}
+
+// changed returns true if the session values are different from when the
+// session was loaded.
+func (s *state) changed() bool {
+	return s.modified || !reflect.DeepEqual(s.values, s.sess.Values)
+}
+
+// Middleware loads the session once for the request and saves it before the
+// response headers are written only if it was modified. Use Current or the
+// getters and setters in the handlers instead of Instance and Save.
+func (i *Info) Middleware(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		stale := i.stale(r)
+
+		// An invalid cookie returns a new session
+		sess, err := i.store.Get(r, i.Name)
+
+		s := &state{
+			sess:     sess,
+			values:   make(map[interface{}]interface{}, len(sess.Values)),
+			modified: stale,
+		}
+		for k, v := range sess.Values {
+			s.values[k] = v
+		}
+
+		// The values are copied first so the time of the request is a change
+		// and the session is saved to keep it from the IdleTimeout
+		if err == nil {
+			s.expired, _ = i.expire(r, sess)
+			// Replace the cookie of an expired session so it is not read again
+			s.modified = s.modified || len(s.expired) > 0
+		}
+
+		r = r.WithContext(context.WithValue(r.Context(), stateKey, s))
+		sw := &saveWriter{ResponseWriter: w, save: func() {
+			if s.saved || !s.changed() {
+				return
+			}
+			s.saved = true
+
+			if err := sess.Save(r, w); err != nil {
+				log.Println("Session save error:", err)
+			}
+		}}
+
+		h.ServeHTTP(sw, r)
+
+		// Save if nothing was written
+		sw.save()
+	})
+}
+
+// saveWriter saves the session before the headers are written.
+type saveWriter struct {
+	http.ResponseWriter
+	save func()
+}
+
+func (w *saveWriter) WriteHeader(code int) {
+	w.save()
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *saveWriter) Write(b []byte) (int, error) {
+	w.save()
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush sends the buffered data if the ResponseWriter supports it.
+func (w *saveWriter) Flush() {
+	w.save()
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack saves the session and takes over the connection if the
+// ResponseWriter supports it so the middleware can wrap websocket routes.
+func (w *saveWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, http.ErrNotSupported
+	}
+
+	w.save()
+	return h.Hijack()
+}
+
+// Unwrap returns the original ResponseWriter.
+func (w *saveWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// *****************************************************************************
+// Request Values
+// *****************************************************************************
+
+// current returns the state from the middleware or nil.
+func current(r *http.Request) *state {
+	s, _ := r.Context().Value(stateKey).(*state)
+	return s
+}
+
+// Current returns the session loaded by the middleware or nil if the
+// middleware is not used.
+func Current(r *http.Request) *sessions.Session {
+	if s := current(r); s != nil {
+		return s.sess
+	}
+
+	return nil
+}
+
+// Get returns the session value for the key or nil.
+func Get(r *http.Request, key string) interface{} {
+	if s := current(r); s != nil {
+		return s.sess.Values[key]
+	}
+
+	return nil
+}
+
+// GetString returns the session value for the key or an empty string if it is
+// missing or not a string.
+func GetString(r *http.Request, key string) string {
+	v, _ := Get(r, key).(string)
+	return v
+}
+
+// GetInt returns the session value for the key or 0 if it is missing or not
+// an int.
+func GetInt(r *http.Request, key string) int {
+	v, _ := Get(r, key).(int)
+	return v
+}
+
+// GetInt64 returns the session value for the key or 0 if it is missing or not
+// an int64.
+func GetInt64(r *http.Request, key string) int64 {
+	v, _ := Get(r, key).(int64)
+	return v
+}
+
+// GetBool returns the session value for the key or false if it is missing or
+// not a bool.
+func GetBool(r *http.Request, key string) bool {
+	v, _ := Get(r, key).(bool)
+	return v
+}
+
+// Set stores the value in the session. The value must be registered with gob
+// if it is not a basic type.
+func Set(r *http.Request, key string, value interface{}) {
+	if s := current(r); s != nil {
+		s.sess.Values[key] = value
+		s.modified = true
+	}
+}
+
+// Delete removes the value from the session.
+func Delete(r *http.Request, key string) {
+	if s := current(r); s != nil {
+		delete(s.sess.Values, key)
+		s.modified = true
+	}
+}
@@ -0,0 +1,204 @@
+package session_test
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/blue-jay/core/session"
+)
+
+// serve runs the handler with the middleware and returns the response.
+func serve(s *session.Info, h http.HandlerFunc, c *http.Cookie) *http.Response {
+	w := httptest.NewRecorder()
+	r, _ := http.NewRequest("GET", "/", nil)
+	if c != nil {
+		r.AddCookie(c)
+	}
+
+	s.Middleware(h).ServeHTTP(w, r)
+
+	return w.Result()
+}
+
+// hijackRecorder is a ResponseRecorder that supports Hijack.
+type hijackRecorder struct {
+	*httptest.ResponseRecorder
+	hijacked bool
+}
+
+func (w *hijackRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	w.hijacked = true
+	return nil, nil, nil
+}
+
+// TestMiddleware ensures the session is saved once only when it is modified.
+func TestMiddleware(t *testing.T) {
+	s := newInfo(session.StoreMemory)
+	if err := s.SetupConfig(); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	// Set a value and write the response
+	res := serve(&s, func(w http.ResponseWriter, r *http.Request) {
+		session.Set(r, "count", 1)
+		fmt.Fprint(w, "hello")
+	}, nil)
+
+	cookies := res.Cookies()
+	if len(cookies) != 1 {
+		t.Fatalf("Expected 1 cookie, got: %v", len(cookies))
+	}
+	c := cookies[0]
+
+	// Read only
+	res = serve(&s, func(w http.ResponseWriter, r *http.Request) {
+		if v := session.GetInt(r, "count"); v != 1 {
+			t.Fatalf("Value should be 1, got: %v", v)
+		}
+		if v := session.GetString(r, "count"); v != "" {
+			t.Fatalf("Value should be empty, got: %v", v)
+		}
+		fmt.Fprint(w, "hello")
+	}, c)
+
+	if len(res.Cookies()) != 0 {
+		t.Fatal("Session should not be saved when it is not modified.")
+	}
+
+	// Change the values directly and write nothing
+	serve(&s, func(w http.ResponseWriter, r *http.Request) {
+		session.Current(r).Values["name"] = "bob"
+	}, c)
+
+	serve(&s, func(w http.ResponseWriter, r *http.Request) {
+		if v := session.GetString(r, "name"); v != "bob" {
+			t.Fatalf("Value should be bob, got: %v", v)
+		}
+		session.Delete(r, "name")
+	}, c)
+
+	serve(&s, func(w http.ResponseWriter, r *http.Request) {
+		if session.Get(r, "name") != nil {
+			t.Fatal("Value should be deleted.")
+		}
+	}, c)
+}
+
+// TestMiddlewareHeaders ensures the cookie is set before the headers are
+// written.
+func TestMiddlewareHeaders(t *testing.T) {
+	s := newInfo(session.StoreCookie)
+	if err := s.SetupConfig(); err != nil {
+		t.Fatal(err)
+	}
+
+	res := serve(&s, func(w http.ResponseWriter, r *http.Request) {
+		session.Set(r, "test", "foo")
+		w.WriteHeader(http.StatusCreated)
+		// Changes after the headers are written are not saved
+		session.Set(r, "test", "bar")
+	}, nil)
+
+	if res.StatusCode != http.StatusCreated {
+		t.Fatalf("Status should be %v, got: %v", http.StatusCreated, res.StatusCode)
+	}
+
+	cookies := res.Cookies()
+	if len(cookies) != 1 {
+		t.Fatalf("Expected 1 cookie, got: %v", len(cookies))
+	}
+
+	val, err := load(&s, cookies[0])
+	if err != nil {
+		t.Fatal(err)
+	} else if val != "foo" {
+		t.Fatalf(`Text should be: "%v", but is wrong: "%v"`, "foo", val)
+	}
+}
+
+// TestMiddlewareReissue ensures a cookie with old keys is reissued.
+func TestMiddlewareReissue(t *testing.T) {
+	old := newInfo(session.StoreCookie)
+	if err := old.SetupConfig(); err != nil {
+		t.Fatal(err)
+	}
+
+	c := save(t, &old, "foo123")
+
+	s := rotated(old)
+	if err := s.SetupConfig(); err != nil {
+		t.Fatal(err)
+	}
+
+	res := serve(&s, func(w http.ResponseWriter, r *http.Request) {}, c)
+
+	if len(res.Cookies()) != 1 {
+		t.Fatal("Cookie should be reissued with the new keys.")
+	}
+
+	// The reissued cookie works after the old keys are removed
+	s.OldKeys = nil
+	if err := s.SetupConfig(); err != nil {
+		t.Fatal(err)
+	}
+
+	if val, err := load(&s, res.Cookies()[0]); err != nil {
+		t.Fatal(err)
+	} else if val != "foo123" {
+		t.Fatalf(`Text should be: "%v", but is wrong: "%v"`, "foo123", val)
+	}
+}
+
+// TestMiddlewareMissing ensures the getters work without the middleware.
+func TestMiddlewareMissing(t *testing.T) {
+	r, _ := http.NewRequest("GET", "/", nil)
+
+	session.Set(r, "test", "foo")
+
+	if session.Current(r) != nil || session.Get(r, "test") != nil {
+		t.Fatal("Session should be missing without the middleware.")
+	}
+}
+
+// TestMiddlewareHijack ensures Hijack is forwarded to the ResponseWriter.
+func TestMiddlewareHijack(t *testing.T) {
+	s := newInfo(session.StoreMemory)
+	if err := s.SetupConfig(); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	h := s.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		session.Set(r, "count", 1)
+		hj, ok := w.(http.Hijacker)
+		if !ok {
+			t.Fatal("ResponseWriter should support Hijack.")
+		}
+		if _, _, err := hj.Hijack(); err != nil {
+			t.Fatal(err)
+		}
+	}))
+
+	r, _ := http.NewRequest("GET", "/", nil)
+	w := &hijackRecorder{ResponseRecorder: httptest.NewRecorder()}
+	h.ServeHTTP(w, r)
+
+	if !w.hijacked {
+		t.Fatal("Hijack was not forwarded.")
+	} else if len(w.Result().Cookies()) != 1 {
+		t.Fatal("Session should be saved before the connection is hijacked.")
+	}
+
+	// The recorder does not support Hijack
+	res := serve(&s, func(w http.ResponseWriter, r *http.Request) {
+		if _, _, err := w.(http.Hijacker).Hijack(); err != http.ErrNotSupported {
+			t.Fatalf("Expected %v, got: %v", http.ErrNotSupported, err)
+		}
+	}, nil)
+	res.Body.Close()
+}
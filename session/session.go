@@ -1,22 +1,57 @@
 // Package session provides a wrapper for gorilla/sessions package.
+//
+// The Store setting selects where the session values are kept: cookie (the
+// default), filesystem, memory, mysql, or postgresql. The mysql and postgresql
+// stores require a database from SetDatabase and create their own table.
+// The memory, mysql, and postgresql stores keep an index of the sessions for
+// each user from SetUser so they can be listed and revoked. The filesystem
+// store is from gorilla/sessions and does not keep an index.
+//
+// To rotate the keys, move the AuthKey and EncryptKey to the top of OldKeys
+// and set new keys. New cookies are signed and encrypted with the new keys and
+// cookies with the old keys are still accepted. The Middleware reissues a
+// cookie with the old keys on the same request so the rotation is transparent.
+// Instance cannot write the response so without the Middleware call Reissue
+// from a middleware of your own.
+//
+// The IdleTimeout counts from the last time the session was saved, not the
+// last request, since the time of the request is stored in the session. Use
+// the Middleware, which saves the session on every request when a timeout is
+// set, or call Save on every request.
 package session
 
 import (
-	"encoding/base64"
+	"database/sql"
 	"errors"
+	"fmt"
 	"net/http"
 
+	"github.com/gorilla/securecookie"
 	"github.com/gorilla/sessions"
 )
 
 // Info holds the session level information.
 type Info struct {
-	Options    sessions.Options `json:"Options"`    // Pulled from: http://www.gorillatoolkit.org/pkg/sessions#Options
-	Name       string           `json:"Name"`       // Name for: http://www.gorillatoolkit.org/pkg/sessions#CookieStore.Get
-	AuthKey    string           `json:"AuthKey"`    // Key for: http://www.gorillatoolkit.org/pkg/sessions#NewCookieStore
-	EncryptKey string           `json:"EncryptKey"` // Key for: http://www.gorillatoolkit.org/pkg/sessions#NewCookieStore
-	CSRFKey    string           `json:"CSRFKey"`    // Key for: http://www.gorillatoolkit.org/pkg/csrf#Protect
-	store      *sessions.CookieStore
+	Options         sessions.Options `json:"Options"`                                                            // Pulled from: http://www.gorillatoolkit.org/pkg/sessions#Options
+	Name            string           `json:"Name" jsonconfig:"required"`                                         // Name for: http://www.gorillatoolkit.org/pkg/sessions#CookieStore.Get
+	AuthKey         string           `json:"AuthKey" jsonconfig:"required,base64=32 64"`                         // Key for: http://www.gorillatoolkit.org/pkg/sessions#NewCookieStore
+	EncryptKey      string           `json:"EncryptKey" jsonconfig:"base64=16 24 32"`                            // Key for: http://www.gorillatoolkit.org/pkg/sessions#NewCookieStore
+	CSRFKey         string           `json:"CSRFKey" jsonconfig:"base64=32"`                                     // Key for: http://www.gorillatoolkit.org/pkg/csrf#Protect
+	Store           string           `json:"Store" jsonconfig:"oneof=cookie filesystem memory mysql postgresql"` // Store for the values: cookie, filesystem, memory, mysql, or postgresql, only memory, mysql, and postgresql keep an index for Sessions
+	Folder          string           `json:"Folder"`                                                             // Folder for the filesystem store, defaults to the temp folder
+	Table           string           `json:"Table"`                                                              // Table for the mysql and postgresql stores, defaults to session
+	OldKeys         []KeyPair        `json:"OldKeys"`                                                            // Keys that are still accepted after a rotation
+	IdleTimeout     int              `json:"IdleTimeout"`                                                        // Seconds since the session was last saved before it expires, 0 to disable
+	AbsoluteTimeout int              `json:"AbsoluteTimeout"`                                                    // Seconds after the session starts that it expires, 0 to disable
+	store           sessions.Store
+	db              *sql.DB
+	current         *securecookie.SecureCookie
+}
+
+// SetDatabase sets the database for the mysql and postgresql stores. It must
+// be called before SetupConfig.
+func (i *Info) SetDatabase(db *sql.DB) {
+	i.db = db
 }
 
 // SetupConfig applies the config and returns an error if it cannot be setup.
@@ -26,37 +61,90 @@ func (i *Info) SetupConfig() error {
 		return errors.New("Session AuthKey is missing and is required as a good practice.")
 	}
 
-	// Decode authentication key
-	auth, err := base64.StdEncoding.DecodeString(i.AuthKey)
-	if err != nil || len(auth) == 0 {
+	// Decode the current and old keys
+	keys, err := i.keyPairs()
+	if err != nil {
 		return err
 	}
 
-	// If the auth key is not set, should error
-	if len(i.EncryptKey) > 0 {
-		// Decode the encrypt key
-		encrypt, err := base64.StdEncoding.DecodeString(i.EncryptKey)
+	// Store the current keys to detect cookies signed with old keys
+	i.current = securecookie.New(keys[0], keys[1]).SetSerializer(securecookie.NopEncoder{}).MaxAge(0).MaxLength(0)
+
+	// Stop the previous store
+	i.Close()
+
+	switch i.Store {
+	case "", StoreCookie:
+		cs := sessions.NewCookieStore(keys...)
+		// Store the options in the cookie store.
+		cs.Options = &i.Options
+		i.store = cs
+	case StoreFilesystem:
+		fs := sessions.NewFilesystemStore(i.Folder, keys...)
+		// The values are not stored in the cookie so they can be any size
+		fs.MaxLength(0)
+		fs.Options = &i.Options
+		i.store = fs
+	case StoreMemory:
+		i.store = newServerStore(newMemoryBackend(), &i.Options, keys...)
+	case StoreMySQL, StorePostgreSQL:
+		if i.db == nil {
+			return fmt.Errorf("Session store %v requires a database from SetDatabase.", i.Store)
+		}
+
+		table := i.Table
+		if len(table) == 0 {
+			table = "session"
+		}
+
+		b, err := newSQLBackend(i.db, i.Store, table)
 		if err != nil {
 			return err
 		}
-		i.store = sessions.NewCookieStore(auth, encrypt)
-	} else {
-		i.store = sessions.NewCookieStore(auth)
+		i.store = newServerStore(b, &i.Options, keys...)
+	default:
+		return fmt.Errorf("Session store is not supported: %v", i.Store)
 	}
 
-	// Store the options in the cookie store.
-	i.store.Options = &i.Options
-
 	return nil
 }
 
+// Close stops the purge of expired sessions for the memory and database
+// stores.
+func (i *Info) Close() {
+	if s, ok := i.store.(*serverStore); ok {
+		s.Close()
+	}
+}
+
 // *****************************************************************************
 // Session Handling
 // *****************************************************************************
 
-// Instance returns an instance of the store.
+// Instance returns an instance of the store. If the session is past the
+// IdleTimeout or AbsoluteTimeout, it is emptied. Use InstanceExpired or
+// Expired with the Middleware to get the reason. A cookie with the OldKeys is
+// accepted and is reissued with the newest keys by the Middleware or Reissue.
 func (i *Info) Instance(r *http.Request) (*sessions.Session, error) {
-	return i.store.Get(r, i.Name)
+	sess, _, err := i.InstanceExpired(r)
+	return sess, err
+}
+
+// Reissue saves the session if the cookie was signed with one of the OldKeys
+// so the cookie is signed and encrypted with the newest keys. The Middleware
+// does this on every request. Without it, call Reissue from a middleware of
+// your own while old keys are in the config.
+func (i *Info) Reissue(w http.ResponseWriter, r *http.Request) error {
+	if !i.stale(r) {
+		return nil
+	}
+
+	sess, err := i.Instance(r)
+	if err != nil {
+		return err
+	}
+
+	return sess.Save(r, w)
 }
 
 // Empty deletes all the current session values.
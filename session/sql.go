@@ -0,0 +1,158 @@
+package session
+
+import (
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+var (
+	// validTable matches the table names that are safe to use in a query
+	validTable = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	// placeholder matches the placeholders in a query
+	placeholder = regexp.MustCompile(`\?`)
+)
+
+// sqlBackend keeps the sessions in a MySQL or PostgreSQL table.
+type sqlBackend struct {
+	db      *sql.DB
+	dialect string
+	table   string
+}
+
+// newSQLBackend returns a backend for the table and creates the table if it
+// does not exist.
+func newSQLBackend(db *sql.DB, dialect string, table string) (*sqlBackend, error) {
+	if !validTable.MatchString(table) {
+		return nil, fmt.Errorf("Session table name is not valid: %v", table)
+	}
+
+	b := &sqlBackend{
+		db:      db,
+		dialect: dialect,
+		table:   table,
+	}
+
+	var err error
+
+	if dialect == StorePostgreSQL {
+		_, err = db.Exec(fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %v (
+			id VARCHAR(64) NOT NULL PRIMARY KEY,
+			data TEXT NOT NULL,
+			expires_at TIMESTAMPTZ NOT NULL,
+			user_id VARCHAR(255) NOT NULL DEFAULT '',
+			ip VARCHAR(45) NOT NULL DEFAULT '',
+			user_agent VARCHAR(255) NOT NULL DEFAULT '',
+			created_at BIGINT NOT NULL,
+			seen_at BIGINT NOT NULL
+			);`, table))
+		if err == nil {
+			_, err = db.Exec(fmt.Sprintf(`CREATE INDEX IF NOT EXISTS %v_expires_at ON %v (expires_at);`, table, table))
+		}
+		if err == nil {
+			_, err = db.Exec(fmt.Sprintf(`CREATE INDEX IF NOT EXISTS %v_user_id ON %v (user_id);`, table, table))
+		}
+	} else {
+		_, err = db.Exec(fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %v (
+			id VARCHAR(64) NOT NULL,
+			data MEDIUMTEXT NOT NULL,
+			expires_at DATETIME NOT NULL,
+			user_id VARCHAR(255) NOT NULL DEFAULT '',
+			ip VARCHAR(45) NOT NULL DEFAULT '',
+			user_agent VARCHAR(255) NOT NULL DEFAULT '',
+			created_at BIGINT NOT NULL,
+			seen_at BIGINT NOT NULL,
+			PRIMARY KEY (id),
+			KEY (expires_at),
+			KEY (user_id)
+			);`, table))
+	}
+
+	return b, err
+}
+
+// query replaces the ? placeholders with $1, $2, etc. for PostgreSQL.
+func (b *sqlBackend) query(q string) string {
+	q = fmt.Sprintf(q, b.table)
+
+	if b.dialect != StorePostgreSQL {
+		return q
+	}
+
+	n := 0
+	return placeholder.ReplaceAllStringFunc(q, func(string) string {
+		n++
+		return "$" + strconv.Itoa(n)
+	})
+}
+
+func (b *sqlBackend) load(id string) (string, error) {
+	var data string
+
+	now := time.Now()
+
+	err := b.db.QueryRow(b.query("SELECT data FROM %v WHERE id = ? AND expires_at > ?;"), id, now.UTC()).Scan(&data)
+	if err == sql.ErrNoRows {
+		return "", ErrSessionNotFound
+	} else if err != nil {
+		return "", err
+	}
+
+	_, err = b.db.Exec(b.query("UPDATE %v SET seen_at = ? WHERE id = ?;"), now.Unix(), id)
+
+	return data, err
+}
+
+func (b *sqlBackend) save(id string, data string, expires time.Time, m meta) error {
+	q := `INSERT INTO %v (id, data, expires_at, user_id, ip, user_agent, created_at, seen_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE data = VALUES(data), expires_at = VALUES(expires_at), user_id = VALUES(user_id),
+		ip = VALUES(ip), user_agent = VALUES(user_agent), seen_at = VALUES(seen_at);`
+
+	if b.dialect == StorePostgreSQL {
+		q = `INSERT INTO %v (id, data, expires_at, user_id, ip, user_agent, created_at, seen_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET data = EXCLUDED.data, expires_at = EXCLUDED.expires_at, user_id = EXCLUDED.user_id,
+		ip = EXCLUDED.ip, user_agent = EXCLUDED.user_agent, seen_at = EXCLUDED.seen_at;`
+	}
+
+	// The created and seen times are stored as Unix seconds so they can be
+	// read without the parseTime parameter for MySQL
+	now := time.Now().Unix()
+	_, err := b.db.Exec(b.query(q), id, data, expires.UTC(), m.user, m.ip, m.agent, now, now)
+	return err
+}
+
+func (b *sqlBackend) erase(id string) error {
+	_, err := b.db.Exec(b.query("DELETE FROM %v WHERE id = ?;"), id)
+	return err
+}
+
+func (b *sqlBackend) purge(now time.Time) error {
+	_, err := b.db.Exec(b.query("DELETE FROM %v WHERE expires_at <= ?;"), now.UTC())
+	return err
+}
+
+func (b *sqlBackend) list(user string) ([]Active, error) {
+	rows, err := b.db.Query(b.query(`SELECT id, created_at, seen_at, ip, user_agent FROM %v
+		WHERE user_id = ? AND expires_at > ?;`), user, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []Active
+
+	for rows.Next() {
+		a := Active{}
+		var created, seen int64
+		if err := rows.Scan(&a.ID, &created, &seen, &a.IP, &a.UserAgent); err != nil {
+			return nil, err
+		}
+		a.Created = time.Unix(created, 0)
+		a.LastSeen = time.Unix(seen, 0)
+		list = append(list, a)
+	}
+
+	return list, rows.Err()
+}
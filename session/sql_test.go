@@ -0,0 +1,316 @@
+package session
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recorder is a database connection that records the statements and returns
+// the same rows for every query.
+type recorder struct {
+	mutex      sync.Mutex
+	statements []statement
+	columns    []string
+	rows       [][]driver.Value
+}
+
+// statement is a query sent to the recorder.
+type statement struct {
+	query string
+	args  []driver.Value
+}
+
+func (d *recorder) Connect(context.Context) (driver.Conn, error) { return d, nil }
+func (d *recorder) Driver() driver.Driver                        { return d }
+func (d *recorder) Open(string) (driver.Conn, error)             { return d, nil }
+func (d *recorder) Prepare(string) (driver.Stmt, error)          { return nil, errors.New("not supported") }
+func (d *recorder) Begin() (driver.Tx, error)                    { return nil, errors.New("not supported") }
+func (d *recorder) Close() error                                 { return nil }
+
+func (d *recorder) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	d.record(query, args)
+	return driver.RowsAffected(1), nil
+}
+
+func (d *recorder) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	d.record(query, args)
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return &rows{columns: d.columns, values: d.rows}, nil
+}
+
+func (d *recorder) record(query string, args []driver.NamedValue) {
+	values := make([]driver.Value, len(args))
+	for i, a := range args {
+		values[i] = a.Value
+	}
+
+	d.mutex.Lock()
+	d.statements = append(d.statements, statement{query, values})
+	d.mutex.Unlock()
+}
+
+// reset clears the statements and sets the rows for the next query.
+func (d *recorder) reset(columns []string, values [][]driver.Value) {
+	d.mutex.Lock()
+	d.statements = nil
+	d.columns = columns
+	d.rows = values
+	d.mutex.Unlock()
+}
+
+// sent returns the statements since the reset.
+func (d *recorder) sent() []statement {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return d.statements
+}
+
+// last returns the only statement sent since the reset.
+func (d *recorder) last(t *testing.T) statement {
+	t.Helper()
+	statements := d.sent()
+	if len(statements) != 1 {
+		t.Fatalf("Expected 1 statement, got: %v", len(statements))
+	}
+	return statements[0]
+}
+
+type rows struct {
+	columns []string
+	values  [][]driver.Value
+}
+
+func (r *rows) Columns() []string { return r.columns }
+func (r *rows) Close() error      { return nil }
+func (r *rows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
+
+// openRecorder returns a database that sends the statements to a new
+// recorder.
+func openRecorder(t *testing.T) (*sql.DB, *recorder) {
+	d := &recorder{}
+	db := sql.OpenDB(d)
+	t.Cleanup(func() { db.Close() })
+	return db, d
+}
+
+// recordedBackend returns a backend for the dialect with the statements from
+// newSQLBackend cleared.
+func recordedBackend(t *testing.T, dialect string) (*sqlBackend, *recorder) {
+	db, d := openRecorder(t)
+
+	b, err := newSQLBackend(db, dialect, "session")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d.reset(nil, nil)
+
+	return b, d
+}
+
+// TestSQLQuery ensures the placeholders are only replaced for PostgreSQL.
+func TestSQLQuery(t *testing.T) {
+	q := "SELECT data FROM %v WHERE id = ? AND expires_at > ?;"
+
+	b := &sqlBackend{dialect: StoreMySQL, table: "session"}
+	expected := "SELECT data FROM session WHERE id = ? AND expires_at > ?;"
+	if received := b.query(q); received != expected {
+		t.Errorf("\n got: %v\nwant: %v", received, expected)
+	}
+
+	b = &sqlBackend{dialect: StorePostgreSQL, table: "session"}
+	expected = "SELECT data FROM session WHERE id = $1 AND expires_at > $2;"
+	if received := b.query(q); received != expected {
+		t.Errorf("\n got: %v\nwant: %v", received, expected)
+	}
+}
+
+// TestSQLCreate ensures the table and indexes are created for each dialect.
+func TestSQLCreate(t *testing.T) {
+	db, d := openRecorder(t)
+
+	if _, err := newSQLBackend(db, StoreMySQL, "session; DROP TABLE user"); err == nil {
+		t.Fatal("Expected an error for a table name that is not valid.")
+	}
+
+	for dialect, expected := range map[string][]string{
+		StoreMySQL: {
+			"CREATE TABLE IF NOT EXISTS session (",
+		},
+		StorePostgreSQL: {
+			"CREATE TABLE IF NOT EXISTS session (",
+			"CREATE INDEX IF NOT EXISTS session_expires_at ON session (expires_at);",
+			"CREATE INDEX IF NOT EXISTS session_user_id ON session (user_id);",
+		},
+	} {
+		d.reset(nil, nil)
+		if _, err := newSQLBackend(db, dialect, "session"); err != nil {
+			t.Fatal(err)
+		}
+
+		statements := d.sent()
+
+		if len(statements) != len(expected) {
+			t.Fatalf("%v: expected %v statements, got: %v", dialect, len(expected), len(statements))
+		}
+		for n, s := range statements {
+			if !strings.HasPrefix(s.query, expected[n]) {
+				t.Errorf("%v:\n got: %v\nwant: %v", dialect, s.query, expected[n])
+			}
+		}
+
+		// MySQL adds the indexes in the table
+		if hasKey := strings.Contains(statements[0].query, "KEY (user_id)"); hasKey != (dialect == StoreMySQL) {
+			t.Errorf("%v: unexpected indexes in the table: %v", dialect, statements[0].query)
+		}
+	}
+}
+
+// TestSQLSave ensures the upsert for each dialect has the session values and
+// the request information.
+func TestSQLSave(t *testing.T) {
+	expires := time.Date(2030, 1, 2, 3, 4, 5, 0, time.FixedZone("EST", -5*3600))
+	m := meta{user: "1", ip: "192.0.2.1", agent: "phone"}
+
+	for dialect, upsert := range map[string]string{
+		StoreMySQL:      "ON DUPLICATE KEY UPDATE",
+		StorePostgreSQL: "ON CONFLICT (id) DO UPDATE",
+	} {
+		b, d := recordedBackend(t, dialect)
+
+		before := time.Now().Unix()
+		if err := b.save("id1", "data", expires, m); err != nil {
+			t.Fatal(err)
+		}
+
+		s := d.last(t)
+		if !strings.HasPrefix(s.query, "INSERT INTO session (") || !strings.Contains(s.query, upsert) {
+			t.Errorf("%v: unexpected query: %v", dialect, s.query)
+		}
+		if dialect == StorePostgreSQL && (strings.Contains(s.query, "?") || !strings.Contains(s.query, "$8")) {
+			t.Errorf("%v: placeholders are not replaced: %v", dialect, s.query)
+		}
+
+		if len(s.args) != 8 {
+			t.Fatalf("%v: expected 8 arguments, got: %v", dialect, len(s.args))
+		}
+		if s.args[0] != "id1" || s.args[1] != "data" || s.args[3] != "1" || s.args[4] != "192.0.2.1" || s.args[5] != "phone" {
+			t.Errorf("%v: unexpected arguments: %v", dialect, s.args)
+		}
+		if e, ok := s.args[2].(time.Time); !ok || !e.Equal(expires) || e.Location() != time.UTC {
+			t.Errorf("%v: expiration should be in UTC: %v", dialect, s.args[2])
+		}
+		if now, ok := s.args[6].(int64); !ok || now < before || s.args[7] != now {
+			t.Errorf("%v: created and seen should be the Unix time: %v, %v", dialect, s.args[6], s.args[7])
+		}
+	}
+}
+
+// TestSQLPurge ensures the expired sessions are deleted.
+func TestSQLPurge(t *testing.T) {
+	b, d := recordedBackend(t, StorePostgreSQL)
+	now := time.Date(2030, 1, 2, 3, 4, 5, 0, time.FixedZone("EST", -5*3600))
+
+	if err := b.purge(now); err != nil {
+		t.Fatal(err)
+	}
+
+	s := d.last(t)
+	expected := "DELETE FROM session WHERE expires_at <= $1;"
+	if s.query != expected {
+		t.Errorf("\n got: %v\nwant: %v", s.query, expected)
+	}
+	if len(s.args) != 1 || !s.args[0].(time.Time).Equal(now) || s.args[0].(time.Time).Location() != time.UTC {
+		t.Errorf("Unexpected arguments: %v", s.args)
+	}
+}
+
+// TestSQLList ensures the sessions for the user are read from the rows.
+func TestSQLList(t *testing.T) {
+	b, d := recordedBackend(t, StorePostgreSQL)
+	d.reset([]string{"id", "created_at", "seen_at", "ip", "user_agent"}, [][]driver.Value{
+		{"id1", int64(100), int64(200), "192.0.2.1", "phone"},
+		{"id2", int64(300), int64(400), "192.0.2.2", "laptop"},
+	})
+
+	list, err := b.list("1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := d.last(t)
+	if !strings.HasPrefix(s.query, "SELECT id, created_at, seen_at, ip, user_agent FROM session") ||
+		!strings.Contains(s.query, "WHERE user_id = $1 AND expires_at > $2;") {
+		t.Errorf("Unexpected query: %v", s.query)
+	}
+	if len(s.args) != 2 || s.args[0] != "1" {
+		t.Errorf("Unexpected arguments: %v", s.args)
+	}
+
+	if len(list) != 2 {
+		t.Fatalf("Expected 2 sessions, got: %v", len(list))
+	}
+	a := list[1]
+	if a.ID != "id2" || a.IP != "192.0.2.2" || a.UserAgent != "laptop" ||
+		a.Created.Unix() != 300 || a.LastSeen.Unix() != 400 {
+		t.Errorf("Unexpected session: %+v", a)
+	}
+}
+
+// TestSQLLoad ensures loading a session records when it was seen.
+func TestSQLLoad(t *testing.T) {
+	b, d := recordedBackend(t, StorePostgreSQL)
+	d.reset([]string{"data"}, [][]driver.Value{{"values"}})
+
+	before := time.Now().Unix()
+	data, err := b.load("id1")
+	if err != nil {
+		t.Fatal(err)
+	} else if data != "values" {
+		t.Fatalf("Expected values, got: %v", data)
+	}
+
+	statements := d.sent()
+
+	if len(statements) != 2 {
+		t.Fatalf("Expected 2 statements, got: %v", len(statements))
+	}
+
+	s := statements[1]
+	if s.query != "UPDATE session SET seen_at = $1 WHERE id = $2;" {
+		t.Errorf("Unexpected query: %v", s.query)
+	}
+	if seen, ok := s.args[0].(int64); !ok || seen < before || s.args[1] != "id1" {
+		t.Errorf("Unexpected arguments: %v", s.args)
+	}
+}
+
+// TestSQLLoadMissing ensures a missing session returns ErrSessionNotFound.
+func TestSQLLoadMissing(t *testing.T) {
+	b, d := recordedBackend(t, StoreMySQL)
+	d.reset([]string{"data"}, nil)
+
+	if _, err := b.load("id1"); err != ErrSessionNotFound {
+		t.Fatalf("Expected %v, got: %v", ErrSessionNotFound, err)
+	}
+
+	s := d.last(t)
+	if s.query != "SELECT data FROM session WHERE id = ? AND expires_at > ?;" || len(s.args) != 2 || s.args[0] != "id1" {
+		t.Errorf("Unexpected statement: %v %v", s.query, s.args)
+	}
+}
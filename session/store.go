@@ -0,0 +1,325 @@
+package session
+
+import (
+	"encoding/base32"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/securecookie"
+	"github.com/gorilla/sessions"
+)
+
+const (
+	// StoreCookie keeps the session values in the cookie
+	StoreCookie = "cookie"
+	// StoreFilesystem keeps the session values in files in the Folder
+	StoreFilesystem = "filesystem"
+	// StoreMemory keeps the session values in memory
+	StoreMemory = "memory"
+	// StoreMySQL keeps the session values in a MySQL table
+	StoreMySQL = "mysql"
+	// StorePostgreSQL keeps the session values in a PostgreSQL table
+	StorePostgreSQL = "postgresql"
+)
+
+var (
+	// PurgeInterval is how often expired sessions are removed from the
+	// memory and database stores
+	PurgeInterval = 10 * time.Minute
+
+	// ErrSessionNotFound is when the session is not in the store or does not
+	// belong to the user
+	ErrSessionNotFound = errors.New("Session not found.")
+)
+
+// backend keeps the encoded session values on the server.
+type backend interface {
+	// load returns the values and records when the session was last seen or
+	// ErrSessionNotFound if the session is missing or expired
+	load(id string) (string, error)
+	// save stores the values until they expire and records the user for
+	// the index
+	save(id string, data string, expires time.Time, m meta) error
+	// erase removes the session
+	erase(id string) error
+	// purge removes all sessions that expired before now
+	purge(now time.Time) error
+	// list returns the sessions for the user that have not expired
+	list(user string) ([]Active, error)
+}
+
+// serverStore is a sessions.Store that only keeps the session ID in the
+// cookie and the values in a backend.
+type serverStore struct {
+	Codecs  []securecookie.Codec
+	Options *sessions.Options
+	backend backend
+
+	stop chan struct{}
+	once sync.Once
+}
+
+// newServerStore returns a store for the backend and starts the purge of
+// expired sessions.
+func newServerStore(b backend, options *sessions.Options, keyPairs ...[]byte) *serverStore {
+	s := &serverStore{
+		Codecs:  securecookie.CodecsFromPairs(keyPairs...),
+		Options: options,
+		backend: b,
+		stop:    make(chan struct{}),
+	}
+
+	for _, c := range s.Codecs {
+		if sc, ok := c.(*securecookie.SecureCookie); ok {
+			// The values are not stored in the cookie so they can be any size
+			sc.MaxLength(0)
+			sc.MaxAge(options.MaxAge)
+		}
+	}
+
+	if PurgeInterval > 0 {
+		go s.purge(PurgeInterval)
+	}
+
+	return s
+}
+
+// Get returns a cached session from the registry for the request.
+func (s *serverStore) Get(r *http.Request, name string) (*sessions.Session, error) {
+	return sessions.GetRegistry(r).Get(s, name)
+}
+
+// New returns the session from the backend or a new session if the cookie is
+// missing or the session has expired.
+func (s *serverStore) New(r *http.Request, name string) (*sessions.Session, error) {
+	session := sessions.NewSession(s, name)
+	opts := *s.Options
+	session.Options = &opts
+	session.IsNew = true
+
+	c, err := r.Cookie(name)
+	if err != nil {
+		return session, nil
+	}
+
+	if err := securecookie.DecodeMulti(name, c.Value, &session.ID, s.Codecs...); err != nil {
+		return session, err
+	}
+
+	data, err := s.backend.load(session.ID)
+	if err == ErrSessionNotFound {
+		// Never reuse an ID that is not in the backend
+		session.ID = ""
+		return session, nil
+	} else if err != nil {
+		return session, err
+	}
+
+	if err := securecookie.DecodeMulti(name, data, &session.Values, s.Codecs...); err != nil {
+		return session, err
+	}
+
+	session.IsNew = false
+
+	return session, nil
+}
+
+// Save writes the session values to the backend and the session ID to the
+// cookie. A MaxAge below zero removes the session.
+func (s *serverStore) Save(r *http.Request, w http.ResponseWriter, session *sessions.Session) error {
+	if session.Options.MaxAge < 0 {
+		if len(session.ID) > 0 {
+			if err := s.backend.erase(session.ID); err != nil {
+				return err
+			}
+		}
+		http.SetCookie(w, sessions.NewCookie(session.Name(), "", session.Options))
+		return nil
+	}
+
+	if len(session.ID) == 0 {
+		session.ID = newID()
+	}
+
+	data, err := securecookie.EncodeMulti(session.Name(), session.Values, s.Codecs...)
+	if err != nil {
+		return err
+	}
+
+	if err := s.backend.save(session.ID, data, expires(session.Options), newMeta(r, session)); err != nil {
+		return err
+	}
+
+	encoded, err := securecookie.EncodeMulti(session.Name(), session.ID, s.Codecs...)
+	if err != nil {
+		return err
+	}
+
+	http.SetCookie(w, sessions.NewCookie(session.Name(), encoded, session.Options))
+
+	return nil
+}
+
+// Close stops the purge of expired sessions.
+func (s *serverStore) Close() {
+	s.once.Do(func() {
+		close(s.stop)
+	})
+}
+
+// purge removes expired sessions at every interval.
+func (s *serverStore) purge(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+			s.backend.purge(time.Now())
+		}
+	}
+}
+
+// expires returns when the session expires on the server. Sessions that only
+// last until the browser is closed are kept for 30 days.
+func expires(options *sessions.Options) time.Time {
+	age := options.MaxAge
+	if age == 0 {
+		age = 86400 * 30
+	}
+
+	return time.Now().Add(time.Duration(age) * time.Second)
+}
+
+// newID returns a random session ID.
+func newID() string {
+	return strings.TrimRight(base32.StdEncoding.EncodeToString(securecookie.GenerateRandomKey(32)), "=")
+}
+
+// *****************************************************************************
+// Memory Backend
+// *****************************************************************************
+
+// memoryBackend keeps the sessions in a map.
+type memoryBackend struct {
+	items map[string]memoryItem
+	users map[string]map[string]bool
+	mutex sync.RWMutex
+}
+
+// memoryItem is a session in memory.
+type memoryItem struct {
+	data    string
+	expires time.Time
+	meta    meta
+	created time.Time
+	seen    time.Time
+}
+
+// newMemoryBackend returns an empty memory backend.
+func newMemoryBackend() *memoryBackend {
+	return &memoryBackend{
+		items: make(map[string]memoryItem),
+		users: make(map[string]map[string]bool),
+	}
+}
+
+func (b *memoryBackend) load(id string) (string, error) {
+	now := time.Now()
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	item, ok := b.items[id]
+	if !ok || now.After(item.expires) {
+		return "", ErrSessionNotFound
+	}
+
+	item.seen = now
+	b.items[id] = item
+
+	return item.data, nil
+}
+
+func (b *memoryBackend) save(id string, data string, expires time.Time, m meta) error {
+	now := time.Now()
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	created := now
+	if item, ok := b.items[id]; ok {
+		created = item.created
+		b.unindex(id, item.meta.user)
+	}
+
+	b.items[id] = memoryItem{data, expires, m, created, now}
+
+	if len(m.user) > 0 {
+		if b.users[m.user] == nil {
+			b.users[m.user] = make(map[string]bool)
+		}
+		b.users[m.user][id] = true
+	}
+
+	return nil
+}
+
+func (b *memoryBackend) erase(id string) error {
+	b.mutex.Lock()
+	b.unindex(id, b.items[id].meta.user)
+	delete(b.items, id)
+	b.mutex.Unlock()
+	return nil
+}
+
+func (b *memoryBackend) purge(now time.Time) error {
+	b.mutex.Lock()
+	for id, item := range b.items {
+		if now.After(item.expires) {
+			b.unindex(id, item.meta.user)
+			delete(b.items, id)
+		}
+	}
+	b.mutex.Unlock()
+	return nil
+}
+
+func (b *memoryBackend) list(user string) ([]Active, error) {
+	now := time.Now()
+	var list []Active
+
+	b.mutex.RLock()
+	for id := range b.users[user] {
+		item := b.items[id]
+		if now.After(item.expires) {
+			continue
+		}
+
+		list = append(list, Active{
+			ID:        id,
+			Created:   item.created,
+			LastSeen:  item.seen,
+			IP:        item.meta.ip,
+			UserAgent: item.meta.agent,
+		})
+	}
+	b.mutex.RUnlock()
+
+	return list, nil
+}
+
+// unindex removes the session from the user index. The mutex must be locked.
+func (b *memoryBackend) unindex(id string, user string) {
+	if ids, ok := b.users[user]; ok {
+		delete(ids, id)
+		if len(ids) == 0 {
+			delete(b.users, user)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package session_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/blue-jay/core/session"
+
+	"github.com/gorilla/sessions"
+)
+
+// newInfo returns a session config for the store.
+func newInfo(store string) session.Info {
+	return session.Info{
+		AuthKey:    "PzCh6FNAB7/jhmlUQ0+25sjJ+WgcJeKR2bAOtnh9UnfVN+WJSBvY/YC80Rs+rbMtwfmSP4FUSxKPtpYKzKFqFA==",
+		EncryptKey: "3oTKCcKjDHMUlV+qur2Ve664SPpSuviyGQ/UqnroUD8=",
+		Name:       "sess",
+		Store:      store,
+		Options: sessions.Options{
+			Path:     "/",
+			MaxAge:   28800,
+			HttpOnly: true,
+		},
+	}
+}
+
+// roundTrip saves a value in the session and then reads it from a new request
+// with the cookie.
+func roundTrip(t *testing.T, s *session.Info, text string) {
+	// Simulate a request
+	w := httptest.NewRecorder()
+	r, err := http.NewRequest("GET", "/", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sess, err := s.Instance(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sess.Values["test"] = text
+	if err := sess.Save(r, w); err != nil {
+		t.Fatal(err)
+	}
+
+	cookies := w.Result().Cookies()
+	if len(cookies) != 1 {
+		t.Fatalf("Expected 1 cookie, got: %v", len(cookies))
+	}
+
+	if len(cookies[0].Value) > 4096 {
+		t.Fatalf("Cookie should only contain the session ID: %v bytes", len(cookies[0].Value))
+	}
+
+	// Simulate the next request
+	r2, err := http.NewRequest("GET", "/", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r2.AddCookie(cookies[0])
+
+	sess2, err := s.Instance(r2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if val, ok := sess2.Values["test"]; !ok {
+		t.Fatalf("Session variable is missing.")
+	} else if val != text {
+		t.Fatalf(`Text should be: "%v", but is wrong: "%v"`, text, val)
+	}
+}
+
+// TestStoreMemory ensures the memory store keeps large values.
+func TestStoreMemory(t *testing.T) {
+	s := newInfo(session.StoreMemory)
+
+	if err := s.SetupConfig(); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	roundTrip(t, &s, strings.Repeat("a", 10000))
+}
+
+// TestStoreFilesystem ensures the filesystem store keeps large values.
+func TestStoreFilesystem(t *testing.T) {
+	dir, err := ioutil.TempDir("", "session")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	s := newInfo(session.StoreFilesystem)
+	s.Folder = dir
+
+	if err := s.SetupConfig(); err != nil {
+		t.Fatal(err)
+	}
+
+	roundTrip(t, &s, strings.Repeat("a", 10000))
+}
+
+// TestStoreMemoryDelete ensures a session is removed with a negative MaxAge.
+func TestStoreMemoryDelete(t *testing.T) {
+	s := newInfo(session.StoreMemory)
+
+	if err := s.SetupConfig(); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	w := httptest.NewRecorder()
+	r, _ := http.NewRequest("GET", "/", nil)
+
+	sess, _ := s.Instance(r)
+	sess.Values["test"] = "foo"
+	sess.Save(r, w)
+	cookie := w.Result().Cookies()[0]
+
+	// Delete the session
+	r2, _ := http.NewRequest("GET", "/", nil)
+	r2.AddCookie(cookie)
+	sess2, _ := s.Instance(r2)
+	sess2.Options.MaxAge = -1
+	sess2.Save(r2, httptest.NewRecorder())
+
+	// The old cookie should return an empty session
+	r3, _ := http.NewRequest("GET", "/", nil)
+	r3.AddCookie(cookie)
+	sess3, err := s.Instance(r3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !sess3.IsNew || len(sess3.Values) > 0 {
+		t.Fatal("Session should be new after it is deleted.")
+	}
+}
+
+// TestStoreFail ensures an unsupported store or missing database fails.
+func TestStoreFail(t *testing.T) {
+	s := newInfo("redis")
+
+	if err := s.SetupConfig(); err == nil {
+		t.Fatal("Store error was expected.")
+	}
+
+	s = newInfo(session.StoreMySQL)
+
+	if err := s.SetupConfig(); err == nil {
+		t.Fatal("Database error was expected.")
+	}
+}
@@ -0,0 +1,67 @@
+package mysql
+
+import (
+	"testing"
+
+	driver "github.com/go-sql-driver/mysql"
+)
+
+// TestDSN ensures the socket and the TLS config are in the Data Source Name.
+func TestDSN(t *testing.T) {
+	base := Info{
+		Username: "root",
+		Password: "pw",
+		Database: "db",
+		Hostname: "db.example.com",
+		Port:     3306,
+	}
+
+	socket := base
+	socket.Socket = "/var/run/mysqld/mysqld.sock"
+
+	tlsDefault := base
+	tlsDefault.TLS = TLS{SkipVerify: true}
+
+	tlsNamed := base
+	tlsNamed.TLS = TLS{Name: "custom", ServerName: "db.example.com"}
+
+	tlsParameter := tlsDefault
+	tlsParameter.Parameter = "tls=skip-verify&parseTime=true"
+
+	for _, tc := range []struct {
+		name     string
+		c        Info
+		expected string
+	}{
+		{"tcp", base, "root:pw@tcp(db.example.com:3306)/db?collation=utf8_unicode_ci&charset=utf8"},
+		{"socket", socket, "root:pw@unix(/var/run/mysqld/mysqld.sock)/db?collation=utf8_unicode_ci&charset=utf8"},
+		{"tls default", tlsDefault, "root:pw@tcp(db.example.com:3306)/db?collation=utf8_unicode_ci&charset=utf8&tls=bluejay-db.example.com"},
+		{"tls named", tlsNamed, "root:pw@tcp(db.example.com:3306)/db?collation=utf8_unicode_ci&charset=utf8&tls=custom"},
+		{"tls parameter", tlsParameter, "root:pw@tcp(db.example.com:3306)/db?tls=skip-verify&parseTime=true&collation=utf8_unicode_ci&charset=utf8"},
+	} {
+		if dsn := tc.c.dsn(true); dsn != tc.expected {
+			t.Errorf("%v:\n got: %v\nwant: %v", tc.name, dsn, tc.expected)
+		}
+	}
+
+	// The database is left out to create it
+	expected := "root:pw@unix(/var/run/mysqld/mysqld.sock)/?collation=utf8_unicode_ci&charset=utf8"
+	if dsn := socket.dsn(false); dsn != expected {
+		t.Errorf("\n got: %v\nwant: %v", dsn, expected)
+	}
+
+	// The driver must read the socket and the TLS parameter
+	cfg, err := driver.ParseDSN(socket.dsn(true))
+	if err != nil {
+		t.Fatal(err)
+	} else if cfg.Net != "unix" || cfg.Addr != socket.Socket {
+		t.Errorf("Socket is not used: %v %v", cfg.Net, cfg.Addr)
+	}
+
+	cfg, err = driver.ParseDSN(tlsParameter.dsn(true))
+	if err != nil {
+		t.Fatal(err)
+	} else if cfg.TLSConfig != "skip-verify" {
+		t.Errorf("TLS parameter is not used: %v", cfg.TLSConfig)
+	}
+}
@@ -0,0 +1,45 @@
+package postgresql
+
+import (
+	"testing"
+
+	"github.com/lib/pq"
+)
+
+// TestDSN ensures a password with reserved characters from ParseURL is
+// escaped in the Data Source Name.
+func TestDSN(t *testing.T) {
+	c, err := ParseURL("postgres://u:p%40s%2Fs@h/db?sslmode=disable")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dsn := c.dsn(true)
+
+	// The driver must be able to parse the DSN
+	if _, err := pq.ParseURL(dsn); err != nil {
+		t.Fatalf("DSN cannot be parsed: %v: %v", dsn, err)
+	}
+
+	back, err := ParseURL(dsn)
+	if err != nil {
+		t.Fatal(err)
+	} else if back != c {
+		t.Fatalf("\n got: %v\nwant: %v", back, c)
+	}
+
+	expected := "postgres://u:p%40s%2Fs@h:5432/?sslmode=disable"
+	if dsn := c.dsn(false); dsn != expected {
+		t.Fatalf("\n got: %v\nwant: %v", dsn, expected)
+	}
+}
+
+// TestURLPort ensures the default port is used when it is not set.
+func TestURLPort(t *testing.T) {
+	c := Info{Username: "u", Hostname: "h", Database: "db"}
+
+	expected := "postgres://u:@h:5432/db"
+	if c.URL() != expected {
+		t.Fatalf("\n got: %v\nwant: %v", c.URL(), expected)
+	}
+}
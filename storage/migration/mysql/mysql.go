@@ -123,6 +123,29 @@ func (c Configuration) New() (*migration.Info, error) {
 	return migration.New(mi, mi.table, c.Migration.Folder)
 }
 
+// NewConnection creates a migration connection to the named database in the
+// config. The connection must be of type MySQL.
+func NewConnection(config *storage.Info, name string) (*migration.Info, error) {
+	var mig *migration.Info
+
+	// Get the named connection
+	con, err := config.Connection(name)
+	if err != nil {
+		return mig, err
+	}
+
+	if con.Type != storage.TypeMySQL {
+		return mig, fmt.Errorf("Connection %v is type %v, not %v.", name, con.Type, storage.TypeMySQL)
+	}
+
+	// Create the migration configuration
+	conf := Configuration{
+		con.MySQL,
+	}
+
+	return conf.New()
+}
+
 // *****************************************************************************
 // Interface
 // *****************************************************************************
@@ -3,9 +3,11 @@ package mysql_test
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
 	"log"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/blue-jay/core/storage"
@@ -388,3 +390,22 @@ UPDATE test_brother SET age = 23 WHERE id = 4;
 UPDATE test_brother SET age = NULL;
 `
 }
+
+// TestNewConnection ensures a named connection must exist and be MySQL.
+func TestNewConnection(t *testing.T) {
+	config := &storage.Info{
+		Connections: map[string]storage.Connection{
+			"legacy": {Type: storage.TypePostgreSQL},
+		},
+	}
+
+	_, err := mysql.NewConnection(config, "missing")
+	if !errors.Is(err, storage.ErrConnectionMissing) {
+		t.Fatalf("Expected %v, got: %v", storage.ErrConnectionMissing, err)
+	}
+
+	_, err = mysql.NewConnection(config, "legacy")
+	if err == nil || !strings.Contains(err.Error(), "not MySQL") {
+		t.Fatalf("Expected an error for a PostgreSQL connection, got: %v", err)
+	}
+}
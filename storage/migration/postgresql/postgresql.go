@@ -0,0 +1,171 @@
+// Package postgresql implements PostgreSQL migrations.
+package postgresql
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/blue-jay/core/storage"
+	driver "github.com/blue-jay/core/storage/driver/postgresql"
+	"github.com/blue-jay/core/storage/migration"
+	"github.com/jmoiron/sqlx"
+)
+
+// Configuration defines the shared configuration interface.
+type Configuration struct {
+	driver.Info
+}
+
+// *****************************************************************************
+// Migration Creation
+// *****************************************************************************
+
+// New creates a migration connection to the database.
+func (c Configuration) New() (*migration.Info, error) {
+	var mig *migration.Info
+
+	// Load the config
+	i := c.Info
+
+	if len(i.MigrationTable) == 0 {
+		return mig, errors.New("PostgreSQL.MigrationTable key is missing in config file.")
+	}
+
+	// Create PostgreSQL entity
+	mi := &Entity{
+		table: i.MigrationTable,
+	}
+
+	// Connect to the database
+	con, err := i.Connect(true)
+
+	// If the database doesn't exist or can't connect
+	if err != nil {
+		// Connect to database without a database
+		con, err = i.Connect(false)
+		if err != nil {
+			return mig, err
+		}
+
+		// Create the database
+		err = i.Create(con)
+		if err != nil {
+			con.Close()
+			return mig, err
+		}
+
+		// Close connection
+		con.Close()
+
+		// Reconnect to the database
+		con, err = i.Connect(true)
+		if err != nil {
+			return mig, err
+		}
+	}
+
+	// Store the connection in the entity
+	mi.sql = con
+
+	return migration.New(mi, mi.table, i.MigrationFolder)
+}
+
+// NewConnection creates a migration connection to the named database in the
+// config. The connection must be of type PostgreSQL.
+func NewConnection(config *storage.Info, name string) (*migration.Info, error) {
+	var mig *migration.Info
+
+	// Get the named connection
+	con, err := config.Connection(name)
+	if err != nil {
+		return mig, err
+	}
+
+	if con.Type != storage.TypePostgreSQL {
+		return mig, fmt.Errorf("Connection %v is type %v, not %v.", name, con.Type, storage.TypePostgreSQL)
+	}
+
+	// Create the migration configuration
+	conf := Configuration{
+		con.PostgreSQL,
+	}
+
+	return conf.New()
+}
+
+// *****************************************************************************
+// Interface
+// *****************************************************************************
+
+// Item defines the migration table.
+type Item struct {
+	ID        uint32    `db:"id"`
+	Name      string    `db:"name"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+// Entity defines fulfills the migration interface.
+type Entity struct {
+	table string
+	sql   *sqlx.DB
+}
+
+// Extension returns the file extension with a period
+func (t *Entity) Extension() string {
+	return ".sql"
+}
+
+// TableExist returns true if the migration table exists
+func (t *Entity) TableExist() error {
+	_, err := t.sql.Exec(fmt.Sprintf("SELECT 1 FROM %v LIMIT 1;", t.table))
+	return err
+}
+
+// CreateTable returns true if the migration was created
+func (t *Entity) CreateTable() error {
+	_, err := t.sql.Exec(fmt.Sprintf(`CREATE TABLE %v (
+		id SERIAL PRIMARY KEY,
+		name VARCHAR(191) NOT NULL UNIQUE,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		);`, t.table))
+	return err
+}
+
+// Status returns last migration name
+func (t *Entity) Status() (string, error) {
+	result := &Item{}
+	err := t.sql.Get(result, fmt.Sprintf("SELECT * FROM %v ORDER BY id DESC LIMIT 1;", t.table))
+
+	// If no rows, then set to nil
+	if err == sql.ErrNoRows {
+		err = nil
+	}
+
+	return result.Name, err
+}
+
+// Migrate runs a query and returns error
+func (t *Entity) Migrate(qry string) error {
+	_, err := t.sql.Exec(qry)
+	return err
+}
+
+// RecordUp adds a record to the database
+func (t *Entity) RecordUp(name string) error {
+	_, err := t.sql.Exec(fmt.Sprintf("INSERT INTO %v (name) VALUES ($1);", t.table), name)
+	return err
+}
+
+// RecordDown removes a record from the database and resets the sequence so
+// the next ID follows the last migration
+func (t *Entity) RecordDown(name string) error {
+	_, err := t.sql.Exec(fmt.Sprintf("DELETE FROM %v WHERE name = $1;", t.table), name)
+	if err != nil {
+		return err
+	}
+
+	_, err = t.sql.Exec(fmt.Sprintf("SELECT setval(pg_get_serial_sequence('%v', 'id'), COALESCE(MAX(id), 0) + 1, false) FROM %v;", t.table, t.table))
+	return err
+}
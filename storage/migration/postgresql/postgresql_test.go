@@ -0,0 +1,40 @@
+package postgresql_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/blue-jay/core/storage"
+	driver "github.com/blue-jay/core/storage/driver/postgresql"
+	"github.com/blue-jay/core/storage/migration/postgresql"
+)
+
+// TestNewConnection ensures a named connection is checked before it connects
+// to the database.
+func TestNewConnection(t *testing.T) {
+	config := &storage.Info{
+		Connections: map[string]storage.Connection{
+			"main": {Type: storage.TypeMySQL},
+			"legacy": {
+				Type:       storage.TypePostgreSQL,
+				PostgreSQL: driver.Info{Hostname: "127.0.0.1", Database: "legacy"},
+			},
+		},
+	}
+
+	_, err := postgresql.NewConnection(config, "missing")
+	if !errors.Is(err, storage.ErrConnectionMissing) {
+		t.Fatalf("Expected %v, got: %v", storage.ErrConnectionMissing, err)
+	}
+
+	_, err = postgresql.NewConnection(config, "main")
+	if err == nil || !strings.Contains(err.Error(), "not PostgreSQL") {
+		t.Fatalf("Expected an error for a MySQL connection, got: %v", err)
+	}
+
+	_, err = postgresql.NewConnection(config, "legacy")
+	if err == nil || !strings.Contains(err.Error(), "MigrationTable") {
+		t.Fatalf("Expected an error for the missing table, got: %v", err)
+	}
+}
@@ -3,14 +3,43 @@ package storage
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
 
 	"github.com/blue-jay/core/jsonconfig"
 	"github.com/blue-jay/core/storage/driver/mysql"
 	"github.com/blue-jay/core/storage/driver/postgresql"
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	// TypeMySQL is the connection type for MySQL
+	TypeMySQL = "MySQL"
+	// TypePostgreSQL is the connection type for PostgreSQL
+	TypePostgreSQL = "PostgreSQL"
+)
+
+var (
+	// ErrConnectionMissing is when the named connection is not in the config
+	ErrConnectionMissing = errors.New("Connection is missing in config file.")
+	// ErrTypeUnsupported is when the connection type is not supported
+	ErrTypeUnsupported = errors.New("Connection type is not supported.")
 )
 
 // Info contains the database connection information for the different storage.
 type Info struct {
+	MySQL       mysql.Info            `json:"MySQL"`
+	PostgreSQL  postgresql.Info       `json:"PostgreSQL"`
+	Connections map[string]Connection `json:"Connections"`
+}
+
+// Connection holds the information for a named database connection. Only the
+// block that matches the Type is used. Use NewConnection in the migration
+// package for the Type to migrate the connection by name.
+type Connection struct {
+	// Type is the driver type: MySQL or PostgreSQL
+	Type       string          `json:"Type" jsonconfig:"required,oneof=MySQL PostgreSQL"`
 	MySQL      mysql.Info      `json:"MySQL"`
 	PostgreSQL postgresql.Info `json:"PostgreSQL"`
 }
@@ -31,3 +60,67 @@ func LoadConfig(configFile string) (*Info, error) {
 	// Return the configuration
 	return config, err
 }
+
+// *****************************************************************************
+// Named Connections
+// *****************************************************************************
+
+// Connection returns the named connection or an error if it is missing or the
+// type is not supported.
+func (c Info) Connection(name string) (Connection, error) {
+	con, ok := c.Connections[name]
+	if !ok {
+		return con, fmt.Errorf("%w: %v", ErrConnectionMissing, name)
+	}
+
+	switch con.Type {
+	case TypeMySQL, TypePostgreSQL:
+		return con, nil
+	}
+
+	return con, fmt.Errorf("%w: %v (%v)", ErrTypeUnsupported, con.Type, name)
+}
+
+// Connect to the named database.
+func (c Info) Connect(name string, specificDatabase bool) (*sqlx.DB, error) {
+	con, err := c.Connection(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return con.Connect(specificDatabase)
+}
+
+// Connect to the database using the driver that matches the Type.
+func (c Connection) Connect(specificDatabase bool) (*sqlx.DB, error) {
+	switch c.Type {
+	case TypeMySQL:
+		return c.MySQL.Connect(specificDatabase)
+	case TypePostgreSQL:
+		return c.PostgreSQL.Connect(specificDatabase)
+	}
+
+	return nil, fmt.Errorf("%w: %v", ErrTypeUnsupported, c.Type)
+}
+
+// ParseURL returns a connection from a URL like the one in the DATABASE_URL
+// environment variable. The type is determined by the scheme: mysql://,
+// postgres://, or postgresql://. The migration settings are not part of the
+// URL so they must be set separately.
+func ParseURL(rawurl string) (Connection, error) {
+	var err error
+	con := Connection{}
+
+	switch {
+	case strings.HasPrefix(rawurl, "mysql://"):
+		con.Type = TypeMySQL
+		con.MySQL, err = mysql.ParseURL(rawurl)
+	case strings.HasPrefix(rawurl, "postgres://"), strings.HasPrefix(rawurl, "postgresql://"):
+		con.Type = TypePostgreSQL
+		con.PostgreSQL, err = postgresql.ParseURL(rawurl)
+	default:
+		err = ErrTypeUnsupported
+	}
+
+	return con, err
+}
@@ -0,0 +1,86 @@
+package storage_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/blue-jay/core/storage"
+)
+
+// TestConnection ensures named connections are loaded properly.
+func TestConnection(t *testing.T) {
+	config, err := storage.LoadConfig("testdata/envtest.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	con, err := config.Connection("analytics")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if con.Type != storage.TypeMySQL {
+		t.Fatalf("\n got: %v\nwant: %v", con.Type, storage.TypeMySQL)
+	}
+
+	expected := "migration_analytics"
+	received := con.MySQL.Migration.Folder
+
+	if expected != received {
+		t.Errorf("\n got: %v\nwant: %v", received, expected)
+	}
+
+	con, err = config.Connection("legacy")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected = "legacy"
+	received = con.PostgreSQL.Database
+
+	if expected != received {
+		t.Errorf("\n got: %v\nwant: %v", received, expected)
+	}
+}
+
+// TestConnectionFail ensures missing and unsupported connections fail.
+func TestConnectionFail(t *testing.T) {
+	config, err := storage.LoadConfig("testdata/envtest.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = config.Connection("missing")
+	if !errors.Is(err, storage.ErrConnectionMissing) {
+		t.Fatalf("Connection should have failed because it is missing: %v", err)
+	}
+
+	config.Connections["unknown"] = storage.Connection{Type: "SQLite"}
+
+	_, err = config.Connection("unknown")
+	if !errors.Is(err, storage.ErrTypeUnsupported) {
+		t.Fatalf("Connection should have failed because the type is not supported: %v", err)
+	}
+
+	_, err = config.Connect("unknown", false)
+	if !errors.Is(err, storage.ErrTypeUnsupported) {
+		t.Fatalf("Connect should have failed because the type is not supported: %v", err)
+	}
+}
+
+// TestParseURL ensures the type is determined by the scheme.
+func TestParseURL(t *testing.T) {
+	con, err := storage.ParseURL("postgres://postgres@localhost/legacy")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if con.Type != storage.TypePostgreSQL {
+		t.Fatalf("\n got: %v\nwant: %v", con.Type, storage.TypePostgreSQL)
+	}
+
+	_, err = storage.ParseURL("sqlite://legacy.db")
+	if err == nil {
+		t.Fatal("ParseURL should have failed because the type is not supported.")
+	}
+}
@@ -0,0 +1,102 @@
+package storage
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/blue-jay/core/storage/driver/mysql"
+	"github.com/blue-jay/core/storage/driver/postgresql"
+	"github.com/jmoiron/sqlx"
+)
+
+var (
+	// TxRetries is the number of times a transaction is retried after a
+	// deadlock or serialization failure
+	TxRetries = 3
+	// TxBackoff is the delay before the first retry, it doubles on each retry
+	TxBackoff = 50 * time.Millisecond
+)
+
+// Tx is a transaction that supports nested transactions through savepoints.
+type Tx struct {
+	*sqlx.Tx
+	depth int
+}
+
+// TxFunc is the function that runs inside a transaction.
+type TxFunc func(*Tx) error
+
+// Transaction runs fn inside a transaction. The transaction is rolled back if
+// fn returns an error or panics, otherwise it is committed. If the error is a
+// deadlock or serialization failure, the whole transaction is run again up to
+// TxRetries times so fn must be safe to call more than once.
+func Transaction(db *sqlx.DB, fn TxFunc) error {
+	delay := TxBackoff
+
+	for attempt := 0; ; attempt++ {
+		err := transaction(db, fn)
+		if err == nil || attempt >= TxRetries || !Retryable(err) {
+			return err
+		}
+
+		time.Sleep(delay)
+		delay *= 2
+	}
+}
+
+// transaction runs fn inside a single transaction.
+func transaction(db *sqlx.DB, fn TxFunc) error {
+	tx, err := db.Beginx()
+	if err != nil {
+		return err
+	}
+
+	// Roll back and pass the panic along
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	if err = fn(&Tx{Tx: tx}); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Transaction runs fn inside a savepoint of the current transaction. The
+// savepoint is rolled back if fn returns an error or panics, otherwise it is
+// released. The error is returned so the outer transaction can decide whether
+// to continue.
+func (t *Tx) Transaction(fn TxFunc) error {
+	name := fmt.Sprintf("bluejay_sp_%d", t.depth+1)
+
+	if _, err := t.Exec("SAVEPOINT " + name); err != nil {
+		return err
+	}
+
+	// Roll back to the savepoint and pass the panic along
+	defer func() {
+		if p := recover(); p != nil {
+			t.Exec("ROLLBACK TO SAVEPOINT " + name)
+			panic(p)
+		}
+	}()
+
+	if err := fn(&Tx{Tx: t.Tx, depth: t.depth + 1}); err != nil {
+		t.Exec("ROLLBACK TO SAVEPOINT " + name)
+		return err
+	}
+
+	_, err := t.Exec("RELEASE SAVEPOINT " + name)
+	return err
+}
+
+// Retryable returns true if the error is a deadlock, lock wait timeout, or
+// serialization failure from any of the supported drivers.
+func Retryable(err error) bool {
+	return mysql.Retryable(err) || postgresql.Retryable(err)
+}
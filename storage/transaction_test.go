@@ -0,0 +1,207 @@
+package storage_test
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/blue-jay/core/storage"
+
+	mysqldriver "github.com/go-sql-driver/mysql"
+	"github.com/jmoiron/sqlx"
+)
+
+// fakeDriver records the statements and fails the first commits.
+type fakeDriver struct {
+	mutex      sync.Mutex
+	statements []string
+	commitErrs []error
+}
+
+func (d *fakeDriver) record(s string) {
+	d.mutex.Lock()
+	d.statements = append(d.statements, s)
+	d.mutex.Unlock()
+}
+
+func (d *fakeDriver) Open(name string) (driver.Conn, error) { return &fakeConn{d}, nil }
+
+type fakeConn struct{ d *fakeDriver }
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) { return &fakeStmt{c.d, query}, nil }
+func (c *fakeConn) Close() error                              { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	c.d.record("BEGIN")
+	return &fakeTx{c.d}, nil
+}
+
+type fakeTx struct{ d *fakeDriver }
+
+func (t *fakeTx) Commit() error {
+	t.d.record("COMMIT")
+	t.d.mutex.Lock()
+	defer t.d.mutex.Unlock()
+	if len(t.d.commitErrs) > 0 {
+		err := t.d.commitErrs[0]
+		t.d.commitErrs = t.d.commitErrs[1:]
+		return err
+	}
+	return nil
+}
+
+func (t *fakeTx) Rollback() error {
+	t.d.record("ROLLBACK")
+	return nil
+}
+
+type fakeStmt struct {
+	d     *fakeDriver
+	query string
+}
+
+func (s *fakeStmt) Close() error  { return nil }
+func (s *fakeStmt) NumInput() int { return -1 }
+func (s *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.d.record(s.query)
+	return driver.RowsAffected(1), nil
+}
+func (s *fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return nil, errors.New("not supported")
+}
+
+var (
+	fake     = &fakeDriver{}
+	fakeOnce sync.Once
+)
+
+// fakeDB returns a database that uses the fake driver.
+func fakeDB(commitErrs ...error) *sqlx.DB {
+	fakeOnce.Do(func() {
+		sql.Register("bluejayfake", fake)
+	})
+
+	fake.mutex.Lock()
+	fake.statements = nil
+	fake.commitErrs = commitErrs
+	fake.mutex.Unlock()
+
+	db, _ := sql.Open("bluejayfake", "")
+	return sqlx.NewDb(db, "bluejayfake")
+}
+
+// TestTransaction ensures the transaction is committed.
+func TestTransaction(t *testing.T) {
+	db := fakeDB()
+
+	err := storage.Transaction(db, func(tx *storage.Tx) error {
+		_, err := tx.Exec("INSERT")
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "BEGIN INSERT COMMIT"
+	received := strings.Join(fake.statements, " ")
+
+	if expected != received {
+		t.Errorf("\n got: %v\nwant: %v", received, expected)
+	}
+}
+
+// TestTransactionRollback ensures the transaction is rolled back on an error
+// and a panic.
+func TestTransactionRollback(t *testing.T) {
+	db := fakeDB()
+
+	err := storage.Transaction(db, func(tx *storage.Tx) error {
+		return errors.New("failed")
+	})
+	if err == nil {
+		t.Fatal("Transaction should have returned the error.")
+	}
+
+	func() {
+		defer func() {
+			if p := recover(); p == nil {
+				t.Fatal("Transaction should have passed the panic along.")
+			}
+		}()
+		storage.Transaction(db, func(tx *storage.Tx) error {
+			panic("failed")
+		})
+	}()
+
+	expected := "BEGIN ROLLBACK BEGIN ROLLBACK"
+	received := strings.Join(fake.statements, " ")
+
+	if expected != received {
+		t.Errorf("\n got: %v\nwant: %v", received, expected)
+	}
+}
+
+// TestTransactionRetry ensures the transaction is retried on a deadlock.
+func TestTransactionRetry(t *testing.T) {
+	storage.TxBackoff = 0
+	db := fakeDB(&mysqldriver.MySQLError{Number: 1213})
+
+	calls := 0
+	err := storage.Transaction(db, func(tx *storage.Tx) error {
+		calls++
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if calls != 2 {
+		t.Errorf("\n got: %v\nwant: %v", calls, 2)
+	}
+
+	// Ensure other errors are not retried
+	db = fakeDB(errors.New("failed"))
+
+	calls = 0
+	storage.Transaction(db, func(tx *storage.Tx) error {
+		calls++
+		return nil
+	})
+
+	if calls != 1 {
+		t.Errorf("\n got: %v\nwant: %v", calls, 1)
+	}
+}
+
+// TestTransactionNested ensures nested transactions use savepoints.
+func TestTransactionNested(t *testing.T) {
+	db := fakeDB()
+
+	err := storage.Transaction(db, func(tx *storage.Tx) error {
+		tx.Transaction(func(tx *storage.Tx) error {
+			return tx.Transaction(func(tx *storage.Tx) error {
+				return errors.New("failed")
+			})
+		})
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := strings.Join([]string{
+		"BEGIN",
+		"SAVEPOINT bluejay_sp_1",
+		"SAVEPOINT bluejay_sp_2",
+		"ROLLBACK TO SAVEPOINT bluejay_sp_2",
+		"ROLLBACK TO SAVEPOINT bluejay_sp_1",
+		"COMMIT",
+	}, " ")
+	received := strings.Join(fake.statements, " ")
+
+	if expected != received {
+		t.Errorf("\n got: %v\nwant: %v", received, expected)
+	}
+}
@@ -0,0 +1,87 @@
+package view_test
+
+import (
+	"embed"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/blue-jay/core/view"
+)
+
+//go:embed testdata/view
+var templateFS embed.FS
+
+// TestRenderFS ensures the templates are read from an embed.FS.
+func TestRenderFS(t *testing.T) {
+	viewInfo := &view.Info{
+		BaseURI:   "/",
+		Extension: "tmpl",
+		Folder:    "testdata/view",
+		Caching:   true,
+	}
+
+	// Set up the view
+	viewInfo.SetTemplates("basetest", []string{})
+	viewInfo.SetFS(templateFS)
+
+	// Simulate a request
+	w := httptest.NewRecorder()
+	r, err := http.NewRequest("GET", "/", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Render the view
+	if err := viewInfo.New("foo/index").Render(w, r); err != nil {
+		t.Fatal(err)
+	}
+
+	received := w.Body.String()
+	expected := `<!DOCTYPE html><div class="container">Bar</div></html>`
+
+	if received != expected {
+		t.Fatalf("\nactual: %v\nexpected: %v", received, expected)
+	}
+}
+
+// TestRenderFSChild ensures the child templates are read from the file system
+// and a missing template is an error.
+func TestRenderFSChild(t *testing.T) {
+	fsys := fstest.MapFS{
+		"base.tmpl":       {Data: []byte(`{{template "content" .}}|{{template "footer" .}}`)},
+		"page/index.tmpl": {Data: []byte(`{{define "content"}}{{.Name}}{{end}}`)},
+		"footer.tmpl":     {Data: []byte(`{{define "footer"}}Footer{{end}}`)},
+	}
+
+	viewInfo := &view.Info{
+		Extension: "tmpl",
+	}
+
+	// Set up the view
+	viewInfo.SetTemplates("base", []string{"footer"})
+	viewInfo.SetFS(fsys)
+
+	w := httptest.NewRecorder()
+	r, _ := http.NewRequest("GET", "/", nil)
+
+	v := viewInfo.New("page/index")
+	v.Vars["Name"] = "Blue Jay"
+	if err := v.Render(w, r); err != nil {
+		t.Fatal(err)
+	}
+
+	if received := w.Body.String(); received != "Blue Jay|Footer" {
+		t.Fatalf("\nactual: %v\nexpected: %v", received, "Blue Jay|Footer")
+	}
+
+	// Missing template
+	w = httptest.NewRecorder()
+	if err := viewInfo.New("page/missing").Render(w, r); err == nil {
+		t.Fatal("Expected an error for a missing template.")
+	} else if !strings.Contains(w.Body.String(), "page/missing.tmpl") {
+		t.Fatalf("Error should name the template: %v", w.Body.String())
+	}
+}
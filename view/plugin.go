@@ -2,6 +2,7 @@ package view
 
 import (
 	"html/template"
+	"io/fs"
 	"net/http"
 )
 
@@ -28,14 +29,22 @@ func (c *Info) modify() []ModifyFunc {
 func (c *Info) SetTemplates(rootTemp string, childTemps []string) {
 	c.mutex.Lock()
 	c.templateCollection = make(map[string]*template.Template)
-	c.mutex.Unlock()
-
 	c.rootTemplate = rootTemp
 	c.childTemplates = childTemps
+	c.mutex.Unlock()
+}
+
+// SetFS will read the templates from the file system instead of the disk. The
+// Folder is the directory in the file system. Pass nil to read from the disk.
+func (c *Info) SetFS(fsys fs.FS) {
+	c.mutex.Lock()
+	c.templateCollection = make(map[string]*template.Template)
+	c.fsys = fsys
+	c.mutex.Unlock()
 }
 
-// ModifyFunc can modify the view before rendering.
-type ModifyFunc func(http.ResponseWriter, *http.Request, *Info)
+// ModifyFunc can modify the view for the request before rendering.
+type ModifyFunc func(http.ResponseWriter, *http.Request, *View)
 
 // SetModifiers will set the modifiers for the View that run
 // before rendering.
@@ -1,10 +1,18 @@
 // Package view provides thread-safe caching of HTML templates.
+//
+// The Info is configured once and is shared by all requests. New returns a
+// View for a single request with its own variables and templates.
+//
+// The templates are read from the Folder on disk unless SetFS is called, such
+// as with an embed.FS, then the Folder is the directory in the file system.
 package view
 
 import (
 	"html/template"
+	"io/fs"
 	"net/http"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
 	"sync"
@@ -23,12 +31,9 @@ type Info struct {
 	Folder    string
 	Caching   bool
 
-	Vars      map[string]interface{}
-	base      string
-	templates []string
-
 	childTemplates []string
 	rootTemplate   string
+	fsys           fs.FS
 
 	extendList  template.FuncMap
 	modifyList  []ModifyFunc
@@ -39,22 +44,36 @@ type Info struct {
 	mutex              sync.RWMutex
 }
 
+// View is a page for a single request.
+type View struct {
+	Vars map[string]interface{}
+
+	info      *Info
+	base      string
+	templates []string
+}
+
 // *****************************************************************************
 // Template Handling
 // *****************************************************************************
 
-// New accepts multiple templates and then returns a new view.
-func (v *Info) New(templateList ...string) *Info {
-	v.Vars = make(map[string]interface{})
-	v.templates = append(v.templates, templateList...)
-	v.base = v.rootTemplate
+// New accepts multiple templates and then returns a new view for the request.
+func (v *Info) New(templateList ...string) *View {
+	v.mutex.RLock()
+	base := v.rootTemplate
+	v.mutex.RUnlock()
 
-	return v
+	return &View{
+		Vars:      make(map[string]interface{}),
+		info:      v,
+		base:      base,
+		templates: append([]string{}, templateList...),
+	}
 }
 
 // Base sets the new base template instead of reading from
 // Template.Root of the config file.
-func (v *Info) Base(base string) *Info {
+func (v *View) Base(base string) *View {
 	// Set the new base template
 	v.base = base
 
@@ -64,59 +83,76 @@ func (v *Info) Base(base string) *Info {
 
 // Render parses one or more templates and outputs to the screen.
 // Also returns an error if anything is wrong.
-func (v *Info) Render(w http.ResponseWriter, r *http.Request) error {
-	// Add the base template
-	v.templates = append([]string{v.base}, v.templates...)
+func (v *View) Render(w http.ResponseWriter, r *http.Request) error {
+	c := v.info
+
+	c.mutex.RLock()
+	children := c.childTemplates
+	fsys := c.fsys
+	c.mutex.RUnlock()
 
-	// Add the child templates
-	v.templates = append(v.templates, v.childTemplates...)
+	// Add the base template and the child templates
+	templates := append([]string{v.base}, v.templates...)
+	templates = append(templates, children...)
 
 	// Set the base template
-	baseTemplate := v.templates[0]
+	baseTemplate := templates[0]
 
 	// Set the key name for caching
-	key := strings.Join(v.templates, ":")
+	key := strings.Join(templates, ":")
 
 	// Get the template collection from cache
-	v.mutex.RLock()
-	tc, ok := v.templateCollection[key]
-	v.mutex.RUnlock()
-
-	// Get the extend list
-	pc := v.extend()
+	c.mutex.RLock()
+	tc, ok := c.templateCollection[key]
+	c.mutex.RUnlock()
 
 	// If the template collection is not cached or caching is disabled
-	if !ok || !v.Caching {
+	if !ok || !c.Caching {
+		paths := make([]string, len(templates))
+
 		// Loop through each template and test the full path
-		for i, name := range v.templates {
+		for i, name := range templates {
+			// Paths in a file system always use a slash
+			if fsys != nil {
+				paths[i] = path.Join(c.Folder, name+"."+c.Extension)
+				continue
+			}
+
 			// Get the absolute path of the root template
-			path, err := filepath.Abs(v.Folder + string(os.PathSeparator) + name + "." + v.Extension)
+			abs, err := filepath.Abs(c.Folder + string(os.PathSeparator) + name + "." + c.Extension)
 			if err != nil {
 				http.Error(w, "Template Path Error: "+err.Error(), http.StatusInternalServerError)
 				return err
 			}
 			// Store the full template path
-			v.templates[i] = path
+			paths[i] = abs
 		}
 
+		var parsed *template.Template
+		var err error
+
 		// Determine if there is an error in the template syntax
-		templates, err := template.New(key).Funcs(pc).ParseFiles(v.templates...)
+		if fsys != nil {
+			parsed, err = template.New(key).Funcs(c.extend()).ParseFS(fsys, paths...)
+		} else {
+			parsed, err = template.New(key).Funcs(c.extend()).ParseFiles(paths...)
+		}
 		if err != nil {
 			http.Error(w, "Template Parse Error: "+err.Error(), http.StatusInternalServerError)
 			return err
 		}
 
 		// Cache the template collection
-		v.mutex.Lock()
-		v.templateCollection[key] = templates
-		v.mutex.Unlock()
+		c.mutex.Lock()
+		c.templateCollection[key] = parsed
+		c.mutex.Unlock()
 
 		// Save the template collection
-		tc = templates
+		tc = parsed
 	}
 
 	// Get the modify list
-	sc := v.modify()
+	sc := c.modify()
 
 	// Loop through and call each one
 	for _, fn := range sc {
@@ -124,7 +160,7 @@ func (v *Info) Render(w http.ResponseWriter, r *http.Request) error {
 	}
 
 	// Display the content to the screen
-	err := tc.Funcs(pc).ExecuteTemplate(w, baseTemplate+"."+v.Extension, v.Vars)
+	err := tc.ExecuteTemplate(w, baseTemplate+"."+c.Extension, v.Vars)
 
 	if err != nil {
 		http.Error(w, "Template File Error: "+err.Error(), http.StatusInternalServerError)
@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync"
 	"testing"
 
 	"github.com/blue-jay/core/view"
@@ -248,7 +249,7 @@ func Map() template.FuncMap {
 }
 
 // Modify sets the variable FOO in the templates.
-func Modify(w http.ResponseWriter, r *http.Request, v *view.Info) {
+func Modify(w http.ResponseWriter, r *http.Request, v *view.View) {
 	v.Vars["FOO"] = "BAR"
 }
 
@@ -325,3 +326,53 @@ func TestMissingBaseTemplate(t *testing.T) {
 		t.Fatalf("\nactual: %v\nexpected: %v", received, expected)
 	}
 }
+
+// TestConcurrent ensures each request has its own variables and templates.
+func TestConcurrent(t *testing.T) {
+	viewInfo := &view.Info{
+		BaseURI:   "/",
+		Extension: "tmpl",
+		Folder:    "testdata/view",
+		Caching:   true,
+	}
+
+	// Set up the view
+	viewInfo.SetTemplates("basetest", []string{})
+	viewInfo.SetModifiers(Modify)
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			w := httptest.NewRecorder()
+			r, _ := http.NewRequest("GET", "/", nil)
+
+			v := viewInfo.New("foo/index")
+			if i%2 == 0 {
+				v = viewInfo.New("foo/index").Base("base2")
+			}
+			v.Vars["ID"] = i
+
+			if err := v.Render(w, r); err != nil {
+				t.Error(err)
+				return
+			}
+
+			expected := `<!DOCTYPE html><div class="container">Bar</div></html>`
+			if i%2 == 0 {
+				expected = "base2 test"
+			}
+
+			if received := w.Body.String(); received != expected {
+				t.Errorf("\nactual: %v\nexpected: %v", received, expected)
+			} else if v.Vars["ID"] != i || v.Vars["FOO"] != "BAR" {
+				t.Errorf("Variables are shared: %v", v.Vars)
+			}
+		}(i)
+	}
+
+	wg.Wait()
+}
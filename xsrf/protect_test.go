@@ -0,0 +1,151 @@
+package xsrf_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/blue-jay/core/view"
+	"github.com/blue-jay/core/xsrf"
+)
+
+// newView returns a view for the testdata.
+func newView() *view.Info {
+	v := &view.Info{
+		BaseURI:   "/",
+		Extension: "tmpl",
+		Folder:    "testdata/view",
+		Caching:   false,
+	}
+	v.SetTemplates("failure", []string{})
+
+	return v
+}
+
+// newProtect returns the handler with the middleware.
+func newProtect(t *testing.T, i xsrf.Info) http.Handler {
+	protect, err := i.Protect(xsrf.Failure(newView()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return protect(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+}
+
+// TestProtect ensures a request without a token fails and a request with the
+// token in the header passes.
+func TestProtect(t *testing.T) {
+	h := newProtect(t, xsrf.Info{
+		AuthKey: "xULAGF5FcWvqHsXaovNFJYfgCt6pedRPROqNvsZjU18=",
+		Exempt:  []string{"/webhook/*", "/ping"},
+	})
+
+	// Get the token
+	w := httptest.NewRecorder()
+	r, _ := http.NewRequest("GET", "/", nil)
+	h.ServeHTTP(w, r)
+
+	token := w.Header().Get("X-CSRF-Token")
+	cookies := w.Result().Cookies()
+	if len(token) == 0 || len(cookies) == 0 {
+		t.Fatal("Token and cookie should be set.")
+	}
+
+	// Missing token
+	w = httptest.NewRecorder()
+	r, _ = http.NewRequest("POST", "/", nil)
+	r.AddCookie(cookies[0])
+	h.ServeHTTP(w, r)
+
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("Status should be %v, got: %v", http.StatusForbidden, w.Code)
+	} else if !strings.HasPrefix(w.Body.String(), "Forbidden: ") {
+		t.Fatalf("Failure view should be rendered: %v", w.Body.String())
+	}
+
+	// Token in the header
+	w = httptest.NewRecorder()
+	r, _ = http.NewRequest("POST", "/", nil)
+	r.AddCookie(cookies[0])
+	r.Header.Set("X-CSRF-Token", token)
+	h.ServeHTTP(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Status should be %v, got: %v", http.StatusOK, w.Code)
+	}
+
+	// Exempt routes
+	for _, path := range []string{"/webhook/stripe", "/ping"} {
+		w = httptest.NewRecorder()
+		r, _ = http.NewRequest("POST", path, nil)
+		h.ServeHTTP(w, r)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("Path %v should be exempt, got: %v", path, w.Code)
+		}
+	}
+}
+
+// TestProtectOrigin ensures only trusted origins can submit.
+func TestProtectOrigin(t *testing.T) {
+	h := newProtect(t, xsrf.Info{
+		AuthKey:        "xULAGF5FcWvqHsXaovNFJYfgCt6pedRPROqNvsZjU18=",
+		TrustedOrigins: []string{"app.example.com"},
+	})
+
+	w := httptest.NewRecorder()
+	r, _ := http.NewRequest("GET", "http://example.com/", nil)
+	h.ServeHTTP(w, r)
+
+	token := w.Header().Get("X-CSRF-Token")
+	cookie := w.Result().Cookies()[0]
+
+	for origin, code := range map[string]int{
+		"http://app.example.com":  http.StatusOK,
+		"http://evil.example.com": http.StatusForbidden,
+	} {
+		w = httptest.NewRecorder()
+		r, _ = http.NewRequest("POST", "http://example.com/", nil)
+		r.AddCookie(cookie)
+		r.Header.Set("X-CSRF-Token", token)
+		r.Header.Set("Origin", origin)
+		h.ServeHTTP(w, r)
+
+		if w.Code != code {
+			t.Fatalf("Status for %v should be %v, got: %v", origin, code, w.Code)
+		}
+	}
+}
+
+// TestProtectKey ensures an invalid key is an error.
+func TestProtectKey(t *testing.T) {
+	if _, err := (xsrf.Info{AuthKey: "c2hvcnQ="}).Protect(nil); err == nil {
+		t.Fatal("Expected an error for a short key.")
+	}
+}
+
+// TestField ensures the hidden input is rendered.
+func TestField(t *testing.T) {
+	i := xsrf.Info{FieldName: "csrf"}
+
+	v := newView()
+	v.SetTemplates("field", []string{})
+	v.SetFuncMaps(i.Map())
+
+	w := httptest.NewRecorder()
+	r, _ := http.NewRequest("GET", "/", nil)
+
+	c := v.New()
+	c.Vars["token"] = `a"b`
+	if err := c.Render(w, r); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `<form><input type="hidden" name="csrf" value="a&#34;b"></form>`
+	if actual := w.Body.String(); actual != expected {
+		t.Fatalf("\nactual: %v\nexpected: %v", actual, expected)
+	}
+}
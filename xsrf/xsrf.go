@@ -2,7 +2,11 @@
 package xsrf
 
 import (
+	"encoding/base64"
+	"fmt"
+	"html/template"
 	"net/http"
+	"strings"
 
 	"github.com/blue-jay/core/view"
 	"github.com/gorilla/csrf"
@@ -10,11 +14,120 @@ import (
 
 // Info holds the config.
 type Info struct {
-	AuthKey string
-	Secure  bool
+	AuthKey        string `jsonconfig:"base64=32"`
+	Secure         bool
+	FieldName      string   // Form field for the token, defaults to _token
+	HeaderName     string   // Request and response header for the token, defaults to X-CSRF-Token
+	Exempt         []string // Paths that are not checked like webhooks, a trailing * matches a prefix
+	TrustedOrigins []string // Hosts that are allowed to submit cross-origin requests
+}
+
+// fieldName returns the form field for the token.
+func (i Info) fieldName() string {
+	if len(i.FieldName) > 0 {
+		return i.FieldName
+	}
+
+	return "_token"
+}
+
+// headerName returns the header for the token.
+func (i Info) headerName() string {
+	if len(i.HeaderName) > 0 {
+		return i.HeaderName
+	}
+
+	return "X-CSRF-Token"
 }
 
 // Token sets token in the template to the CSRF token.
-func Token(w http.ResponseWriter, r *http.Request, v *view.Info) {
+func Token(w http.ResponseWriter, r *http.Request, v *view.View) {
 	v.Vars["token"] = csrf.Token(r)
 }
+
+// Protect returns the middleware that checks the token for requests that are
+// not GET, HEAD, OPTIONS, or TRACE. The token is read from the form field or
+// the header and is also set in the response header so Ajax clients can read
+// it. The failure handler is called when the check fails, use Failure to
+// render a view or nil to return a plain 403 error.
+func (i Info) Protect(failure http.Handler) (func(http.Handler) http.Handler, error) {
+	key, err := base64.StdEncoding.DecodeString(i.AuthKey)
+	if err != nil {
+		return nil, err
+	} else if len(key) != 32 {
+		return nil, fmt.Errorf("CSRF AuthKey must be 32 bytes, got: %v", len(key))
+	}
+
+	opts := []csrf.Option{
+		csrf.Secure(i.Secure),
+		csrf.FieldName(i.fieldName()),
+		csrf.RequestHeader(i.headerName()),
+		csrf.TrustedOrigins(i.TrustedOrigins),
+	}
+
+	if failure != nil {
+		opts = append(opts, csrf.ErrorHandler(failure))
+	}
+
+	protect := csrf.Protect(key, opts...)
+
+	return func(h http.Handler) http.Handler {
+		cs := protect(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set(i.headerName(), csrf.Token(r))
+			h.ServeHTTP(w, r)
+		}))
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if i.exempt(r.URL.Path) {
+				r = csrf.UnsafeSkipCheck(r)
+			}
+
+			// The Referer is only checked for HTTPS
+			if !i.Secure && r.TLS == nil {
+				r = csrf.PlaintextHTTPRequest(r)
+			}
+
+			cs.ServeHTTP(w, r)
+		})
+	}, nil
+}
+
+// exempt returns true if the path is not checked.
+func (i Info) exempt(path string) bool {
+	for _, p := range i.Exempt {
+		if strings.HasSuffix(p, "*") {
+			if strings.HasPrefix(path, strings.TrimSuffix(p, "*")) {
+				return true
+			}
+		} else if path == p {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Failure returns a handler that renders the templates with a 403 status. The
+// reason the check failed is in the reason variable.
+func Failure(v *view.Info, templates ...string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c := v.New(templates...)
+		c.Vars["reason"] = fmt.Sprint(csrf.FailureReason(r))
+		w.WriteHeader(http.StatusForbidden)
+		c.Render(w, r)
+	})
+}
+
+// Map returns a template.FuncMap CSRF_FIELD which returns the hidden input
+// with the token from the Token modifier.
+func (i Info) Map() template.FuncMap {
+	f := make(template.FuncMap)
+
+	f["CSRF_FIELD"] = func(m map[string]interface{}) template.HTML {
+		token, _ := m["token"].(string)
+		return template.HTML(fmt.Sprintf(`<input type="hidden" name="%v" value="%v">`,
+			template.HTMLEscapeString(i.fieldName()), template.HTMLEscapeString(token)))
+	}
+
+	return f
+}